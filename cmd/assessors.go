@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/cache"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// assessorPool builds one DocAssessor per distinct provider configuration so
+// that rules sharing a configuration also share a client. Every assessor, or
+// every voter of a consensus, retries transient failures and shares a single
+// rate limiter, if one is configured. When store is not nil, results are cached in it.
+type assessorPool struct {
+	assessors map[string]assessor.DocAssessor
+	retry     config.RetryOptions
+	limiter   *assessor.RateLimiter
+	store     *cache.Store
+	// recordDir, if set, is where the providers' API responses are
+	// recorded as fixtures for the replay provider.
+	recordDir string
+}
+
+func newAssessorPool(cfg *config.Config, store *cache.Store) *assessorPool {
+	p := &assessorPool{
+		assessors: make(map[string]assessor.DocAssessor),
+		retry:     cfg.Retry,
+		store:     store,
+	}
+	if rpm := cfg.RateLimit.RequestsPerMinute; rpm > 0 {
+		p.limiter = assessor.NewRateLimiter(rpm)
+	}
+	return p
+}
+
+// wrap makes an assessor share the pool's rate limiter and retry its
+// transient failures.
+func (p *assessorPool) wrap(a assessor.DocAssessor) assessor.DocAssessor {
+	if p.limiter != nil {
+		a = assessor.NewRateLimitedAssessor(a, p.limiter)
+	}
+	return assessor.NewRetryingAssessor(a, p.retry)
+}
+
+// get returns the DocAssessor for the given provider and options, creating it
+// on first use.
+func (p *assessorPool) get(provider string, opts config.ProviderOptions) (assessor.DocAssessor, error) {
+	optsKey, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provider options: %w", err)
+	}
+	key := provider + ":" + string(optsKey)
+
+	if a, ok := p.assessors[key]; ok {
+		return a, nil
+	}
+	var a assessor.DocAssessor
+	switch {
+	case p.recordDir != "":
+		// Record the raw responses of the provider itself, so that
+		// cached assessments are never recorded.
+		a, err = assessor.NewRecordingAssessor(provider, opts, p.recordDir)
+		if err == nil {
+			a = p.wrap(a)
+		}
+	case provider == "consensus":
+		// Every vote is a request of its own, so each voter is
+		// rate-limited and retried rather than the consensus as a whole.
+		a, err = assessor.NewConsensusAssessor(opts, p.wrap)
+	default:
+		a, err = assessor.New(provider, opts)
+		if err == nil {
+			a = p.wrap(a)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The dummy and replay providers are free and instant, so there's
+	// nothing to save.
+	if p.store != nil && provider != "dummy" && provider != "replay" {
+		a = cache.NewAssessor(a, p.store, provider, opts)
+	}
+	p.assessors[key] = a
+	return a, nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+func TestAssessorPool(t *testing.T) {
+	pool := newAssessorPool(&config.Config{}, nil)
+
+	first, err := pool.get("ollama", config.ProviderOptions{Model: "llama3"})
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	again, err := pool.get("ollama", config.ProviderOptions{Model: "llama3"})
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	other, err := pool.get("ollama", config.ProviderOptions{Model: "qwen2.5-coder"})
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+
+	if first != again {
+		t.Errorf("expected the same assessor for identical configurations")
+	}
+	if first == other {
+		t.Errorf("expected a new assessor for a different configuration")
+	}
+	if len(pool.assessors) != 2 {
+		t.Errorf("expected 2 pooled assessors, got %d", len(pool.assessors))
+	}
+
+	if _, err := pool.get("unknown", config.ProviderOptions{}); err == nil {
+		t.Errorf("expected an error for an unknown provider")
+	}
+}
+
+func TestAssessorPool_Consensus(t *testing.T) {
+	pool := newAssessorPool(&config.Config{RateLimit: config.RateLimitOptions{RequestsPerMinute: 60}}, nil)
+
+	a, err := pool.get("consensus", config.ProviderOptions{Consensus: &config.ConsensusOptions{
+		Voters: []config.Voter{{Provider: "dummy", Samples: 3}},
+	}})
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	// The voters are rate-limited and retried, not the consensus, so that
+	// each vote counts against the rate limit.
+	if _, ok := a.(*assessor.ConsensusAssessor); !ok {
+		t.Errorf("get() = %T, want an unwrapped *assessor.ConsensusAssessor", a)
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/driftee-ai/drift/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Records the current drift, so that drift check --baseline only fails on new drift.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		rep, interrupted := runCheck(cmd, nil, "")
+		if interrupted {
+			os.Exit(ExitInterrupted)
+		}
+		// A baseline without the rules that failed would make their drift
+		// fail every later check.
+		if n := rep.Errors(); n > 0 {
+			if err := report.WriteText(os.Stderr, rep); err != nil {
+				exitf(ExitProviderError, "failed to write report: %v", err)
+			}
+			exitf(ExitProviderError, "%d rules could not be checked; no baseline was written", n)
+		}
+
+		baseline := report.NewBaseline(rep)
+		if err := baseline.Write(output); err != nil {
+			exitf(ExitUsageError, "failed to write baseline %s: %v", output, err)
+		}
+		fmt.Printf("Wrote %d baseline entries for %d out-of-sync rules to %s.\n", len(baseline.Entries), baselineRules(baseline), output)
+	},
+}
+
+// baselineRules returns the number of distinct rules in a baseline.
+func baselineRules(b *report.Baseline) int {
+	rules := make(map[string]bool)
+	for _, e := range b.Entries {
+		rules[e.Rule] = true
+	}
+	return len(rules)
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	addRunFlags(baselineCmd)
+	baselineCmd.Flags().StringP("output", "o", report.DefaultBaselinePath, "Path of the baseline file to write")
+}
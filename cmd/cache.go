@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/driftee-ai/drift/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manages the cache of assessment results.",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Removes all cached assessment results.",
+	Run: func(cmd *cobra.Command, args []string) {
+		store := cacheStore(cmd)
+		n, err := store.Clear()
+		if err != nil {
+			exitf(ExitUsageError, "failed to clear cache %s: %v", store.Dir(), err)
+		}
+		fmt.Printf("Removed %d cached results from %s.\n", n, store.Dir())
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Shows the number and size of cached assessment results.",
+	Run: func(cmd *cobra.Command, args []string) {
+		store := cacheStore(cmd)
+		stats, err := store.Stats()
+		if err != nil {
+			exitf(ExitUsageError, "failed to read cache %s: %v", store.Dir(), err)
+		}
+		fmt.Printf("Cache directory: %s\n", store.Dir())
+		fmt.Printf("Entries: %d\n", stats.Entries)
+		fmt.Printf("Total size: %d bytes\n", stats.Bytes)
+	},
+}
+
+func cacheStore(cmd *cobra.Command) *cache.Store {
+	dir, _ := cmd.Flags().GetString("cache-dir")
+	return cache.NewStore(dir)
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd, cacheStatsCmd)
+	cacheCmd.PersistentFlags().String("cache-dir", cache.DefaultDir, "Directory of cached assessment results")
+}
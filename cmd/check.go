@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/cache"
 	"github.com/driftee-ai/drift/pkg/config"
 	"github.com/driftee-ai/drift/pkg/files"
+	"github.com/driftee-ai/drift/pkg/report"
 	"github.com/driftee-ai/drift/pkg/rules"
 	"github.com/spf13/cobra"
 )
@@ -16,91 +24,371 @@ var checkCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Checks for drift between your code and your documentation.",
 	Run: func(cmd *cobra.Command, args []string) {
-		configFile, _ := cmd.Flags().GetString("config")
 		changedFiles, _ := cmd.Flags().GetStringSlice("changed-files")
+		format, _ := cmd.Flags().GetString("format")
+		junitOut, _ := cmd.Flags().GetString("junit-out")
+		recordDir, _ := cmd.Flags().GetString("record")
+		baselinePath, _ := cmd.Flags().GetString("baseline")
+		failOn, _ := cmd.Flags().GetString("fail-on")
+		failOnError, _ := cmd.Flags().GetBool("fail-on-error")
 
-		cfg, err := config.Load(configFile)
-		if err != nil {
-			log.Fatalf("failed to load config file %s: %v", configFile, err)
+		if !isSupportedFormat(format) {
+			exitf(ExitUsageError, "unknown output format %q (supported: %s)", format, strings.Join(report.Formats, ", "))
 		}
-
-		docAssessor, err := assessor.New(cfg.Provider)
-		if err != nil {
-			log.Fatalf("failed to create assessor: %v", err)
+		if !config.IsSeverity(failOn) {
+			exitf(ExitUsageError, "unknown severity %q for --fail-on (supported: %s)", failOn, strings.Join(config.Severities, ", "))
+		}
+		var baseline *report.Baseline
+		if baselinePath != "" {
+			var err error
+			if baseline, err = report.LoadBaseline(baselinePath); err != nil {
+				exitf(ExitUsageError, "failed to load baseline: %v", err)
+			}
 		}
 
-		triggeredRules, err := rules.FilterTriggeredRules(cfg.Rules, changedFiles)
-		if err != nil {
-			log.Fatalf("failed to filter rules based on changed files: %v", err)
+		rep, interrupted := runCheck(cmd, changedFiles, recordDir)
+		rep.FailOn = failOn
+		if baseline != nil {
+			rep.BaselinePath = baselinePath
+			rep.ApplyBaseline(baseline)
 		}
 
-		fmt.Printf("Loaded %d rules from %s (provider: %s)\n", len(cfg.Rules), configFile, cfg.Provider)
-		if len(changedFiles) > 0 {
-			fmt.Printf("Filtering rules based on %d changed files. %d rules were triggered.\n", len(changedFiles), len(triggeredRules))
+		if err := report.Write(os.Stdout, rep, format); err != nil {
+			exitf(ExitProviderError, "failed to write report: %v", err)
 		}
-		allInSync := true
-		for _, rule := range triggeredRules {
-			fmt.Printf("  - Rule: %s\n", rule.Name)
-
-			// Find and read code files
-			codeFiles, err := files.FindFiles(rule.Code)
-			if err != nil {
-				log.Printf("Error finding code files for rule '%s': %v", rule.Name, err)
-				allInSync = false
-				continue
-			}
-			codeContents, err := files.ReadFiles(codeFiles)
-			if err != nil {
-				log.Printf("Error reading code content for rule '%s': %v", rule.Name, err)
-				allInSync = false
-				continue
-			}
-			totalSize := 0
-			for _, content := range codeContents {
-				totalSize += len(content)
-			}
-			fmt.Printf("    Found %d code files, total size: %d bytes\n", len(codeFiles), totalSize)
-
-			// Find and read docs files
-			docFiles, err := files.FindFiles(rule.Docs)
-			if err != nil {
-				log.Printf("Error finding doc files for rule '%s': %v", rule.Name, err)
-				allInSync = false
-				continue
-			}
-			docContent, err := files.ReadAndConcatenate(docFiles)
-			if err != nil {
-				log.Printf("Error reading doc content for rule '%s': %v", rule.Name, err)
-				allInSync = false
-				continue
-			}
-			fmt.Printf("    Found %d doc files, total size: %d bytes\n", len(docFiles), len(docContent))
-
-			// Assess the drift
-			result, err := docAssessor.Assess(docContent, codeContents)
-			if err != nil {
-				log.Printf("Error assessing drift for rule '%s': %v", rule.Name, err)
-				allInSync = false // Consider assessment error as out of sync
-				continue
+		if junitOut != "" {
+			if err := writeReportFile(junitOut, rep, report.FormatJUnit); err != nil {
+				exitf(ExitProviderError, "failed to write JUnit report: %v", err)
 			}
+		}
 
-			if result.IsInSync {
-				fmt.Printf("    Result: In Sync\n")
-			} else {
-				fmt.Printf("    Result: Out of Sync (%s)\n", result.Reason)
-				allInSync = false // Set flag to false
+		if interrupted {
+			os.Exit(ExitInterrupted)
+		}
+		if code := exitCode(rep, failOnError); code != ExitOK {
+			os.Exit(code)
+		}
+	},
+}
+
+// runCheck loads the configuration named by the command's flags and checks
+// the rules triggered by changedFiles, or every rule if there are none. It
+// exits on configuration errors, and reports whether the check was
+// interrupted.
+func runCheck(cmd *cobra.Command, changedFiles []string, recordDir string) (*report.Report, bool) {
+	configFile, _ := cmd.Flags().GetString("config")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	ruleTimeout, _ := cmd.Flags().GetDuration("rule-timeout")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	cacheDir, _ := cmd.Flags().GetString("cache-dir")
+
+	cfg, err := config.Load(configFile)
+	if err != nil {
+		exitf(ExitUsageError, "failed to load config file %s: %v", configFile, err)
+	}
+
+	if concurrency == 0 {
+		concurrency = cfg.Concurrency
+	}
+	if concurrency == 0 {
+		concurrency = defaultConcurrency
+	}
+	if concurrency < 0 {
+		exitf(ExitUsageError, "concurrency must be positive, got %d", concurrency)
+	}
+
+	triggeredRules, err := rules.FilterTriggeredRules(cfg.Rules, changedFiles)
+	if err != nil {
+		exitf(ExitUsageError, "failed to filter rules based on changed files: %v", err)
+	}
+
+	// Cached assessments never reach the provider, so recording implies
+	// --no-cache.
+	var store *cache.Store
+	if !noCache && recordDir == "" {
+		store = cache.NewStore(cacheDir)
+	}
+	pool := newAssessorPool(cfg, store)
+	pool.recordDir = recordDir
+	ruleAssessors, prompts, err := prepareRules(cfg, triggeredRules, pool)
+	if err != nil {
+		exitf(ExitUsageError, "%v", err)
+	}
+
+	rep := &report.Report{
+		ToolVersion:  version,
+		ConfigPath:   configFile,
+		Provider:     cfg.Provider,
+		Model:        cfg.ProviderOptions.Model,
+		TotalRules:   len(cfg.Rules),
+		ChangedFiles: changedFiles,
+		Rules:        []report.RuleResult{},
+		SkippedRules: skippedRuleNames(cfg.Rules, triggeredRules),
+		RuleNames:    ruleNames(cfg.Rules),
+		StartedAt:    time.Now(),
+	}
+	// Cancel in-flight assessments on Ctrl-C or when the global timeout
+	// expires, and still report the rules that finished.
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	rep.Rules = checkRules(ctx, cfg, triggeredRules, ruleAssessors, checkOptions{
+		concurrency: concurrency,
+		ruleTimeout: ruleTimeout,
+		prompts:     prompts,
+	})
+	rep.Duration = time.Since(rep.StartedAt)
+	return rep, ctx.Err() == context.Canceled
+}
+
+// defaultConcurrency is the number of rules checked in parallel when neither
+// the flag nor the config file sets it.
+const defaultConcurrency = 1
+
+// prepareRules creates the assessor and the prompt of each rule up front, so
+// that configuration problems surface before any rule is checked.
+func prepareRules(cfg *config.Config, rules []config.Rule, pool *assessorPool) ([]assessor.DocAssessor, []*assessor.Prompt, error) {
+	ruleAssessors := make([]assessor.DocAssessor, len(rules))
+	prompts := make([]*assessor.Prompt, len(rules))
+	for i, rule := range rules {
+		provider, opts := cfg.ProviderFor(rule)
+		pooled, err := pool.get(provider, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create assessor for rule '%s': %w", rule.Name, err)
+		}
+		ruleAssessors[i], err = assessor.NewBudgetedAssessor(pooled, provider, cfg.TokenBudgetFor(rule))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid token budget for rule '%s': %w", rule.Name, err)
+		}
+		prompts[i], err = assessor.NewPrompt(cfg.PromptFor(rule))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid prompt for rule '%s': %w", rule.Name, err)
+		}
+		if severity := cfg.SeverityFor(rule); !config.IsSeverity(severity) {
+			return nil, nil, fmt.Errorf("invalid severity for rule '%s': %q is not one of %s", rule.Name, severity, strings.Join(config.Severities, ", "))
+		}
+		if minConfidence := cfg.MinConfidenceFor(rule); minConfidence < 0 || minConfidence > 1 {
+			return nil, nil, fmt.Errorf("invalid min_confidence for rule '%s': %v is not between 0 and 1", rule.Name, minConfidence)
+		}
+	}
+	return ruleAssessors, prompts, nil
+}
+
+// checkOptions controls how checkRules checks rules.
+type checkOptions struct {
+	// concurrency is the number of rules checked in parallel.
+	concurrency int
+	// ruleTimeout bounds the check of a single rule, if set.
+	ruleTimeout time.Duration
+	// prompts holds the prompt of each rule, in the same order as the
+	// rules. Missing or nil prompts mean the default prompt.
+	prompts []*assessor.Prompt
+}
+
+// checkRules checks rules using up to opts.concurrency workers. The results
+// are in the same order as rules, regardless of the order in which checks
+// finish. Once ctx is done, rules that haven't started are reported as errors
+// without being checked.
+func checkRules(ctx context.Context, cfg *config.Config, rules []config.Rule, ruleAssessors []assessor.DocAssessor, opts checkOptions) []report.RuleResult {
+	results := make([]report.RuleResult, len(rules))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < opts.concurrency && w < len(rules); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i] = report.RuleResult{
+						Name:   rules[i].Name,
+						Status: report.StatusError,
+						Error:  fmt.Sprintf("not checked: %v", err),
+					}
+				} else {
+					var prompt *assessor.Prompt
+					if i < len(opts.prompts) {
+						prompt = opts.prompts[i]
+					}
+					results[i] = checkRuleWithTimeout(ctx, rules[i], ruleAssessors[i], prompt, opts.ruleTimeout)
+					applyMinConfidence(&results[i], cfg.MinConfidenceFor(rules[i]))
+				}
+				provider, providerOpts := cfg.ProviderFor(rules[i])
+				results[i].Provider = provider
+				results[i].Model = providerOpts.Model
+				results[i].Severity = cfg.SeverityFor(rules[i])
 			}
+		}()
+	}
+
+	for i := range rules {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func checkRuleWithTimeout(ctx context.Context, rule config.Rule, docAssessor assessor.DocAssessor, prompt *assessor.Prompt, timeout time.Duration) report.RuleResult {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return checkRule(ctx, rule, docAssessor, prompt)
+}
+
+// checkRule reads the files matched by a rule and assesses them for drift.
+// Errors are recorded in the result rather than returned, so that one failing
+// rule doesn't prevent the others from being checked.
+func checkRule(ctx context.Context, rule config.Rule, docAssessor assessor.DocAssessor, prompt *assessor.Prompt) report.RuleResult {
+	start := time.Now()
+	result := report.RuleResult{Name: rule.Name}
+	fail := func(format string, args ...interface{}) report.RuleResult {
+		result.Status = report.StatusError
+		result.Error = fmt.Sprintf(format, args...)
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	// Find and read code files
+	codeFiles, err := files.FindFiles(rule.CodePatterns())
+	if err != nil {
+		return fail("failed to find code files: %v", err)
+	}
+	codeContents, err := files.ReadFiles(codeFiles)
+	if err != nil {
+		return fail("failed to read code content: %v", err)
+	}
+	totalSize := 0
+	for _, content := range codeContents {
+		totalSize += len(content)
+	}
+	result.CodeFiles = &report.FileStats{Count: len(codeFiles), Bytes: totalSize, Paths: codeFiles}
+
+	// Find and read docs files
+	docFiles, err := files.FindFiles(rule.DocPatterns())
+	if err != nil {
+		return fail("failed to find doc files: %v", err)
+	}
+	docContent, suppressions, err := files.ReadAndConcatenate(docFiles)
+	if err != nil {
+		// Broken or expired suppressions must be fixed in the docs, so
+		// they fail the check regardless of --fail-on-error.
+		var expired *files.ExpiredSuppressionError
+		var marker *files.MarkerError
+		result.Invalid = errors.As(err, &expired) || errors.As(err, &marker)
+		return fail("failed to read doc content: %v", err)
+	}
+	result.DocFiles = &report.FileStats{Count: len(docFiles), Bytes: len(docContent), Paths: docFiles}
+	result.Suppressions = suppressions
+
+	// Assess the drift
+	assessment, err := docAssessor.Assess(ctx, assessor.Request{
+		Rule:         rule,
+		DocContent:   docContent,
+		CodeContents: codeContents,
+		Prompt:       prompt,
+	})
+	if err != nil {
+		return fail("failed to assess drift: %v", err)
+	}
+
+	result.Result = assessment
+	if assessment.IsInSync {
+		result.Status = report.StatusInSync
+	} else {
+		result.Status = report.StatusOutOfSync
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// applyMinConfidence downgrades the drift of a rule to a warning when the
+// assessment is less confident than minConfidence. Assessments without a
+// confidence are taken at their word.
+func applyMinConfidence(result *report.RuleResult, minConfidence float64) {
+	result.MinConfidence = minConfidence
+	if result.Status != report.StatusOutOfSync || result.Result.Confidence == nil {
+		return
+	}
+	if *result.Result.Confidence < minConfidence {
+		result.Status = report.StatusWarning
+	}
+}
+
+// ruleNames returns the names of rules, in order.
+func ruleNames(rules []config.Rule) []string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+	}
+	return names
+}
+
+// skippedRuleNames returns the names of the rules that were not triggered.
+func skippedRuleNames(all, triggered []config.Rule) []string {
+	isTriggered := make(map[string]bool, len(triggered))
+	for _, rule := range triggered {
+		isTriggered[rule.Name] = true
+	}
+	skipped := []string{}
+	for _, rule := range all {
+		if !isTriggered[rule.Name] {
+			skipped = append(skipped, rule.Name)
 		}
+	}
+	return skipped
+}
+
+// writeReportFile renders the report to the file at path.
+func writeReportFile(path string, rep *report.Report, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(f, rep, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
 
-		if !allInSync {
-			fmt.Println("Drift detected.")
-			os.Exit(1)
+func isSupportedFormat(format string) bool {
+	for _, f := range report.Formats {
+		if f == format {
+			return true
 		}
-	},
+	}
+	return false
+}
+
+// addRunFlags registers the flags that control how rules are checked, shared
+// by the commands that run a check.
+func addRunFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("config", "c", ".drift.yaml", "Path to the drift configuration file")
+	cmd.Flags().Int("concurrency", 0, "Number of rules to check in parallel (default: the config's concurrency, or 1)")
+	cmd.Flags().Duration("timeout", 0, "Maximum duration of the whole check, e.g. 10m (default: no limit)")
+	cmd.Flags().Duration("rule-timeout", 0, "Maximum duration of a single rule's check, e.g. 2m (default: no limit)")
+	cmd.Flags().Bool("no-cache", false, "Assess every rule, ignoring and not updating cached results")
+	cmd.Flags().String("cache-dir", cache.DefaultDir, "Directory of cached assessment results")
 }
 
 func init() {
 	rootCmd.AddCommand(checkCmd)
-	checkCmd.Flags().StringP("config", "c", ".drift.yaml", "Path to the drift configuration file")
+	addRunFlags(checkCmd)
 	checkCmd.Flags().StringSliceP("changed-files", "f", []string{}, "List of changed files to check for drift")
+	checkCmd.Flags().StringP("format", "o", report.FormatText, "Output format: "+strings.Join(report.Formats, ", "))
+	checkCmd.Flags().String("junit-out", "", "Also write a JUnit XML report to this path")
+	checkCmd.Flags().String("record", "", "Record the provider's API responses in this directory as fixtures for the replay provider (implies --no-cache)")
+	checkCmd.Flags().String("baseline", "", "Only fail on drift that is not in this baseline file, written by drift baseline")
+	checkCmd.Flags().String("fail-on", config.SeverityError, "Minimum severity of drift that fails the check: "+strings.Join(config.Severities, ", "))
+	checkCmd.Flags().Bool("fail-on-error", true, "Exit with a non-zero code when a rule could not be checked")
 }
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/report"
+)
+
+// slowAssessor waits before answering and tracks how many assessments run at
+// the same time.
+type slowAssessor struct {
+	delay   time.Duration
+	reason  string
+	running *int32
+	maxSeen *int32
+	mu      *sync.Mutex
+}
+
+func (a *slowAssessor) Assess(ctx context.Context, req assessor.Request) (*assessor.AssessmentResult, error) {
+	n := atomic.AddInt32(a.running, 1)
+	a.mu.Lock()
+	if n > *a.maxSeen {
+		*a.maxSeen = n
+	}
+	a.mu.Unlock()
+
+	defer atomic.AddInt32(a.running, -1)
+
+	select {
+	case <-time.After(a.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &assessor.AssessmentResult{IsInSync: false, Reason: a.reason}, nil
+}
+
+func TestCheckRules_OrderedAndConcurrent(t *testing.T) {
+	var running, maxSeen int32
+	var mu sync.Mutex
+
+	cfg := &config.Config{Provider: "dummy"}
+	var rules []config.Rule
+	var assessors []assessor.DocAssessor
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("rule-%d", i)
+		rules = append(rules, config.Rule{Name: name})
+		// Earlier rules take longer, so they finish last.
+		assessors = append(assessors, &slowAssessor{
+			delay:   time.Duration(6-i) * 10 * time.Millisecond,
+			reason:  name,
+			running: &running,
+			maxSeen: &maxSeen,
+			mu:      &mu,
+		})
+	}
+
+	results := checkRules(context.Background(), cfg, rules, assessors, checkOptions{concurrency: 3})
+
+	if len(results) != len(rules) {
+		t.Fatalf("expected %d results, got %d", len(rules), len(results))
+	}
+	for i, result := range results {
+		if result.Name != rules[i].Name {
+			t.Errorf("result %d is for rule %q, want %q", i, result.Name, rules[i].Name)
+		}
+		if result.Status != report.StatusOutOfSync || result.Result.Reason != rules[i].Name {
+			t.Errorf("result %d has the wrong assessment: %+v", i, result)
+		}
+		if result.Provider != "dummy" {
+			t.Errorf("result %d provider = %q, want %q", i, result.Provider, "dummy")
+		}
+	}
+	if maxSeen < 2 || maxSeen > 3 {
+		t.Errorf("expected between 2 and 3 concurrent assessments, saw %d", maxSeen)
+	}
+}
+
+func TestCheckRules_Timeouts(t *testing.T) {
+	var running, maxSeen int32
+	var mu sync.Mutex
+	newAssessor := func(delay time.Duration) assessor.DocAssessor {
+		return &slowAssessor{delay: delay, running: &running, maxSeen: &maxSeen, mu: &mu}
+	}
+
+	cfg := &config.Config{Provider: "dummy"}
+	rules := []config.Rule{{Name: "fast"}, {Name: "hung"}}
+	assessors := []assessor.DocAssessor{newAssessor(0), newAssessor(time.Hour)}
+
+	results := checkRules(context.Background(), cfg, rules, assessors, checkOptions{
+		concurrency: 2,
+		ruleTimeout: 20 * time.Millisecond,
+	})
+
+	if results[0].Status != report.StatusOutOfSync {
+		t.Errorf("expected the fast rule to finish, got %+v", results[0])
+	}
+	if results[1].Status != report.StatusError || !strings.Contains(results[1].Error, "deadline exceeded") {
+		t.Errorf("expected the hung rule to time out, got %+v", results[1])
+	}
+}
+
+func TestCheckRules_Canceled(t *testing.T) {
+	var running, maxSeen int32
+	var mu sync.Mutex
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cfg := &config.Config{Provider: "dummy"}
+	rules := []config.Rule{{Name: "a"}, {Name: "b"}}
+	assessors := []assessor.DocAssessor{
+		&slowAssessor{running: &running, maxSeen: &maxSeen, mu: &mu},
+		&slowAssessor{running: &running, maxSeen: &maxSeen, mu: &mu},
+	}
+
+	results := checkRules(ctx, cfg, rules, assessors, checkOptions{concurrency: 1})
+
+	for i, result := range results {
+		if result.Name != rules[i].Name || result.Status != report.StatusError {
+			t.Errorf("expected rule %q to be reported as not checked, got %+v", rules[i].Name, result)
+		}
+		if !strings.Contains(result.Error, "not checked") {
+			t.Errorf("unexpected error for rule %q: %s", rules[i].Name, result.Error)
+		}
+	}
+	if maxSeen != 0 {
+		t.Errorf("expected no assessments after cancellation, saw %d", maxSeen)
+	}
+}
+
+func TestPrepareRules_InvalidPromptTemplate(t *testing.T) {
+	cfg := &config.Config{
+		Provider: "dummy",
+		Rules: []config.Rule{{
+			Name:   "Users",
+			Prompt: &config.PromptOptions{Template: "{{.Rule.Nmae}}"},
+		}},
+	}
+	_, _, err := prepareRules(cfg, cfg.Rules, newAssessorPool(cfg, nil))
+	if err == nil || !strings.Contains(err.Error(), "invalid prompt for rule 'Users'") || !strings.Contains(err.Error(), "Nmae") {
+		t.Errorf("prepareRules() error = %v, want an invalid prompt error", err)
+	}
+}
+
+func TestApplyMinConfidence(t *testing.T) {
+	confidence := func(c float64) *float64 { return &c }
+
+	tests := []struct {
+		name          string
+		status        report.Status
+		confidence    *float64
+		minConfidence float64
+		want          report.Status
+	}{
+		{name: "confident drift", status: report.StatusOutOfSync, confidence: confidence(0.9), minConfidence: 0.7, want: report.StatusOutOfSync},
+		{name: "drift at the minimum", status: report.StatusOutOfSync, confidence: confidence(0.7), minConfidence: 0.7, want: report.StatusOutOfSync},
+		{name: "unsure drift", status: report.StatusOutOfSync, confidence: confidence(0.4), minConfidence: 0.7, want: report.StatusWarning},
+		{name: "unknown confidence", status: report.StatusOutOfSync, minConfidence: 0.7, want: report.StatusOutOfSync},
+		{name: "no minimum", status: report.StatusOutOfSync, confidence: confidence(0.1), want: report.StatusOutOfSync},
+		{name: "unsure in sync", status: report.StatusInSync, confidence: confidence(0.1), minConfidence: 0.7, want: report.StatusInSync},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := report.RuleResult{
+				Status: tt.status,
+				Result: &assessor.AssessmentResult{IsInSync: tt.status == report.StatusInSync, Confidence: tt.confidence},
+			}
+			applyMinConfidence(&result, tt.minConfidence)
+			if result.Status != tt.want {
+				t.Errorf("status = %q, want %q", result.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/eval"
+	"github.com/driftee-ai/drift/pkg/files"
+	"github.com/driftee-ai/drift/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Measures how well a provider detects drift on a corpus of labelled test cases.",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		provider, _ := cmd.Flags().GetString("provider")
+		model, _ := cmd.Flags().GetString("model")
+		format, _ := cmd.Flags().GetString("format")
+
+		if format != report.FormatText && format != report.FormatJSON {
+			exitf(ExitUsageError, "unknown output format %q (supported: %s, %s)", format, report.FormatText, report.FormatJSON)
+		}
+
+		cases, err := eval.Discover(dir)
+		if err != nil {
+			exitf(ExitUsageError, "failed to find test cases: %v", err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		summary, err := evaluate(ctx, cases, evalOverrides{provider: provider, model: model})
+		if err != nil {
+			exitf(ExitUsageError, "%v", err)
+		}
+		interrupted := ctx.Err() != nil
+		stop()
+
+		if format == report.FormatJSON {
+			err = eval.WriteJSON(os.Stdout, summary)
+		} else {
+			err = eval.WriteText(os.Stdout, summary)
+		}
+		if err != nil {
+			exitf(ExitProviderError, "failed to write evaluation: %v", err)
+		}
+		if interrupted {
+			os.Exit(ExitInterrupted)
+		}
+	},
+}
+
+// evalOverrides replace the provider settings of every case.
+type evalOverrides struct {
+	provider string
+	model    string
+}
+
+// apply overrides the provider settings of cfg. A different provider drops
+// the options of the case and of its rules, since they don't carry over
+// between providers.
+func (o evalOverrides) apply(cfg *config.Config) {
+	if o.provider != "" && o.provider != cfg.Provider {
+		cfg.Provider = o.provider
+		cfg.ProviderOptions = config.ProviderOptions{}
+		for i := range cfg.Rules {
+			cfg.Rules[i].Provider = ""
+			cfg.Rules[i].ProviderOptions = nil
+		}
+	}
+	if o.model != "" {
+		cfg.ProviderOptions.Model = o.model
+		for i := range cfg.Rules {
+			if cfg.Rules[i].ProviderOptions != nil {
+				cfg.Rules[i].ProviderOptions.Model = ""
+			}
+		}
+	}
+}
+
+// evaluate checks every rule of every case, one case at a time, and compares
+// the verdicts with the labels. Results are never cached, so that every run
+// measures the provider.
+func evaluate(ctx context.Context, cases []eval.Case, overrides evalOverrides) (*eval.Summary, error) {
+	var provider, model string
+	results := make([]eval.Result, 0, len(cases))
+	for _, c := range cases {
+		cfg, err := config.Load(c.ConfigPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config of case %s: %w", c.Name, err)
+		}
+		overrides.apply(cfg)
+		if provider == "" {
+			provider, model = cfg.Provider, cfg.ProviderOptions.Model
+		}
+
+		caseRules, err := resolveCaseRules(c.Dir, cfg.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules in case %s: %w", c.Name, err)
+		}
+		ruleAssessors, prompts, err := prepareRules(cfg, caseRules, newAssessorPool(cfg, nil))
+		if err != nil {
+			return nil, fmt.Errorf("case %s: %w", c.Name, err)
+		}
+
+		ruleResults := checkRules(ctx, cfg, caseRules, ruleAssessors, checkOptions{concurrency: 1, prompts: prompts})
+		results = append(results, caseResult(c, ruleResults))
+	}
+	return eval.Summarize(provider, model, results), nil
+}
+
+// caseResult combines the results of the rules of a case: the case has
+// drift if any rule is out of sync, and fails if any rule could not be
+// checked.
+func caseResult(c eval.Case, ruleResults []report.RuleResult) eval.Result {
+	result := eval.Result{Case: c}
+	var reasons, errors []string
+	for _, r := range ruleResults {
+		switch r.Status {
+		case report.StatusOutOfSync:
+			result.GotDrift = true
+			reasons = append(reasons, r.Result.Reason)
+		case report.StatusError:
+			errors = append(errors, fmt.Sprintf("%s: %s", r.Name, r.Error))
+		}
+	}
+	result.Reason = strings.Join(reasons, "; ")
+	result.Error = strings.Join(errors, "; ")
+	return result
+}
+
+// resolveCaseRules returns copies of rules whose patterns are relative to the
+// working directory. Patterns are resolved relative to the case directory;
+// patterns that match nothing there are kept as they are, for cases written
+// to be run from the repository root.
+func resolveCaseRules(dir string, rules []config.Rule) ([]config.Rule, error) {
+	if filepath.IsAbs(dir) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		if dir, err = filepath.Rel(wd, dir); err != nil {
+			return nil, err
+		}
+	}
+
+	resolve := func(patterns []string) ([]string, error) {
+		if patterns == nil {
+			return nil, nil
+		}
+		resolved := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			// Exclusions are resolved like the pattern they negate.
+			negated := strings.HasPrefix(pattern, "!")
+			pattern = strings.TrimPrefix(pattern, "!")
+			candidate := filepath.ToSlash(filepath.Join(dir, pattern))
+			matches, err := files.FindFiles([]string{candidate})
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) > 0 {
+				resolved[i] = candidate
+			} else {
+				resolved[i] = pattern
+			}
+			if negated {
+				resolved[i] = "!" + resolved[i]
+			}
+		}
+		return resolved, nil
+	}
+
+	resolvedRules := make([]config.Rule, len(rules))
+	for i, rule := range rules {
+		var err error
+		resolvedRules[i] = rule
+		if resolvedRules[i].Code, err = resolve(rule.Code); err != nil {
+			return nil, err
+		}
+		if resolvedRules[i].Docs, err = resolve(rule.Docs); err != nil {
+			return nil, err
+		}
+		if resolvedRules[i].ExcludeCode, err = resolve(rule.ExcludeCode); err != nil {
+			return nil, err
+		}
+		if resolvedRules[i].ExcludeDocs, err = resolve(rule.ExcludeDocs); err != nil {
+			return nil, err
+		}
+	}
+	return resolvedRules, nil
+}
+
+func init() {
+	rootCmd.AddCommand(evalCmd)
+	evalCmd.Flags().String("dir", "testdata/e2e", "Directory of labelled test cases")
+	evalCmd.Flags().String("provider", "", "Provider to evaluate (default: the provider of each case's config)")
+	evalCmd.Flags().String("model", "", "Model to evaluate (default: the model of each case's config)")
+	evalCmd.Flags().StringP("format", "o", report.FormatText, "Output format: text, json")
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/eval"
+)
+
+func TestResolveCaseRules(t *testing.T) {
+	t.Chdir("..")
+
+	// The cases in testdata/e2e mix patterns relative to the case with
+	// patterns relative to the repository root.
+	rules := []config.Rule{
+		{Name: "case-relative", Code: []string{"code.go"}, Docs: []string{"*.md"}},
+		{Name: "root-relative", Code: []string{"testdata/e2e/true_negatives/in_sync_example/code.go"}},
+		{Name: "exclusions", Code: []string{"*.go", "!code.go"}, ExcludeDocs: []string{"docs.md"}},
+	}
+	got, err := resolveCaseRules("testdata/e2e/true_negatives/in_sync_example", rules)
+	if err != nil {
+		t.Fatalf("resolveCaseRules() error = %v", err)
+	}
+
+	want := []config.Rule{
+		{
+			Name: "case-relative",
+			Code: []string{"testdata/e2e/true_negatives/in_sync_example/code.go"},
+			Docs: []string{"testdata/e2e/true_negatives/in_sync_example/*.md"},
+		},
+		{Name: "root-relative", Code: []string{"testdata/e2e/true_negatives/in_sync_example/code.go"}},
+		{
+			Name:        "exclusions",
+			Code:        []string{"testdata/e2e/true_negatives/in_sync_example/*.go", "!testdata/e2e/true_negatives/in_sync_example/code.go"},
+			ExcludeDocs: []string{"testdata/e2e/true_negatives/in_sync_example/docs.md"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("resolveCaseRules() = %+v, want %+v", got, want)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Chdir("..")
+
+	cases, err := eval.Discover("testdata/e2e")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	summary, err := evaluate(context.Background(), cases, evalOverrides{provider: "dummy"})
+	if err != nil {
+		t.Fatalf("evaluate() error = %v", err)
+	}
+
+	// The dummy provider finds every case in sync.
+	if summary.Provider != "dummy" || summary.Cases != len(cases) {
+		t.Errorf("summary = %+v, want %d cases evaluated with dummy", summary, len(cases))
+	}
+	if summary.TrueNegatives != 2 || summary.FalseNegatives != 2 || summary.Errors != 0 {
+		t.Errorf("summary = %+v, want 2 true negatives and 2 false negatives", summary)
+	}
+}
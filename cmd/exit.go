@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"log"
+	"os"
+
+	"github.com/driftee-ai/drift/pkg/report"
+)
+
+// Exit codes returned by drift.
+const (
+	// ExitOK means every checked rule is in sync.
+	ExitOK = 0
+	// ExitDrift means at least one rule is out of sync.
+	ExitDrift = 1
+	// ExitUsageError means the command line or the configuration is invalid,
+	// including missing provider credentials, or a rule's files are invalid,
+	// e.g. because of a malformed or expired drift:ignore suppression.
+	ExitUsageError = 2
+	// ExitProviderError means at least one rule could not be checked, e.g.
+	// because the provider API failed or a file could not be read.
+	ExitProviderError = 3
+	// ExitInterrupted means the check was interrupted with Ctrl-C or
+	// SIGTERM. It follows the shell convention of 128 + SIGINT.
+	ExitInterrupted = 130
+)
+
+// exitf logs a message and exits with the given code.
+func exitf(code int, format string, args ...interface{}) {
+	log.Printf(format, args...)
+	os.Exit(code)
+}
+
+// exitCode returns the exit code for a finished check. Drift takes precedence
+// over errors, since it is a definite result, but only fails the check when
+// its severity reaches rep.FailOn. Rules that are invalid, e.g. because of a
+// malformed or expired suppression, always fail the check; other errors only
+// fail it when failOnError is set.
+func exitCode(rep *report.Report, failOnError bool) int {
+	if rep.HasFailingDrift() {
+		return ExitDrift
+	}
+	if rep.Invalid() > 0 {
+		return ExitUsageError
+	}
+	if rep.Errors() > 0 && failOnError {
+		return ExitProviderError
+	}
+	return ExitOK
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/report"
+)
+
+func TestExitCode(t *testing.T) {
+	inSync := report.RuleResult{Status: report.StatusInSync}
+	drift := report.RuleResult{Status: report.StatusOutOfSync}
+	failed := report.RuleResult{Status: report.StatusError}
+	invalid := report.RuleResult{Status: report.StatusError, Invalid: true}
+	warning := report.RuleResult{Status: report.StatusWarning}
+	infoWarning := report.RuleResult{Status: report.StatusWarning, Severity: config.SeverityInfo}
+	minorDrift := report.RuleResult{Status: report.StatusOutOfSync, Severity: config.SeverityWarning}
+	infoDrift := report.RuleResult{Status: report.StatusOutOfSync, Severity: config.SeverityInfo}
+
+	tests := []struct {
+		name        string
+		rules       []report.RuleResult
+		failOn      string
+		failOnError bool
+		want        int
+	}{
+		{name: "no rules", rules: nil, failOnError: true, want: ExitOK},
+		{name: "all in sync", rules: []report.RuleResult{inSync, inSync}, failOnError: true, want: ExitOK},
+		{name: "drift", rules: []report.RuleResult{inSync, drift}, failOnError: true, want: ExitDrift},
+		{name: "low-confidence drift", rules: []report.RuleResult{inSync, warning}, failOnError: true, want: ExitOK},
+		{name: "low-confidence drift failing on warnings", rules: []report.RuleResult{inSync, warning}, failOn: config.SeverityWarning, failOnError: true, want: ExitDrift},
+		{name: "low-confidence drift failing on info", rules: []report.RuleResult{warning}, failOn: config.SeverityInfo, failOnError: true, want: ExitDrift},
+		{name: "low-confidence info-level drift failing on warnings", rules: []report.RuleResult{infoWarning}, failOn: config.SeverityWarning, failOnError: true, want: ExitOK},
+		{name: "low-confidence info-level drift failing on info", rules: []report.RuleResult{infoWarning}, failOn: config.SeverityInfo, failOnError: true, want: ExitDrift},
+		{name: "warning-level drift", rules: []report.RuleResult{inSync, minorDrift}, failOnError: true, want: ExitOK},
+		{name: "warning-level drift failing on warnings", rules: []report.RuleResult{minorDrift}, failOn: config.SeverityWarning, failOnError: true, want: ExitDrift},
+		{name: "info-level drift failing on warnings", rules: []report.RuleResult{infoDrift}, failOn: config.SeverityWarning, failOnError: true, want: ExitOK},
+		{name: "info-level drift failing on info", rules: []report.RuleResult{infoDrift}, failOn: config.SeverityInfo, failOnError: true, want: ExitDrift},
+		{name: "warning-level drift and error", rules: []report.RuleResult{minorDrift, failed}, failOnError: true, want: ExitProviderError},
+		{name: "error", rules: []report.RuleResult{inSync, failed}, failOnError: true, want: ExitProviderError},
+		{name: "error ignored", rules: []report.RuleResult{inSync, failed}, failOnError: false, want: ExitOK},
+		{name: "drift and error", rules: []report.RuleResult{failed, drift}, failOnError: true, want: ExitDrift},
+		{name: "invalid", rules: []report.RuleResult{inSync, invalid}, failOnError: true, want: ExitUsageError},
+		{name: "invalid with errors ignored", rules: []report.RuleResult{inSync, invalid}, failOnError: false, want: ExitUsageError},
+		{name: "invalid and error", rules: []report.RuleResult{failed, invalid}, failOnError: true, want: ExitUsageError},
+		{name: "drift and invalid", rules: []report.RuleResult{invalid, drift}, failOnError: true, want: ExitDrift},
+		{name: "drift with errors ignored", rules: []report.RuleResult{failed, drift}, failOnError: false, want: ExitDrift},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exitCode(&report.Report{Rules: tt.rules, FailOn: tt.failOn}, tt.failOnError)
+			if got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
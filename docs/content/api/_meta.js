@@ -5,5 +5,14 @@ export default {
   check: {
     title: "check",
   },
+  baseline: {
+    title: "baseline",
+  },
+  cache: {
+    title: "cache",
+  },
+  eval: {
+    title: "eval",
+  },
 };
 
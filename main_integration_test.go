@@ -3,6 +3,10 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"strings"
@@ -160,4 +164,431 @@ func TestCheckCommand_SubtleDrift(t *testing.T) {
 	if !strings.Contains(string(output), expectedOutput) {
 		t.Errorf("Expected output to contain '%s', but got:\n%s", expectedOutput, string(output))
 	}
+}
+
+func TestCheckCommand_JSONFormat(t *testing.T) {
+	cmd := exec.Command("./"+testBinaryName, "check", "--config", "testdata/.drift.dummy.yaml", "--format", "json")
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("check command failed: %v\nOutput:\n%s", err, string(output))
+	}
+
+	var rep struct {
+		Provider string `json:"provider"`
+		InSync   bool   `json:"in_sync"`
+		Rules    []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(output, &rep); err != nil {
+		t.Fatalf("output is not valid JSON: %v\nOutput:\n%s", err, string(output))
+	}
+	if rep.Provider != "dummy" || !rep.InSync {
+		t.Errorf("unexpected report: %+v", rep)
+	}
+	if len(rep.Rules) != 1 || rep.Rules[0].Status != "in_sync" {
+		t.Errorf("expected one in_sync rule, got %+v", rep.Rules)
+	}
+}
+
+func TestBaselineCommand(t *testing.T) {
+	baseline := t.TempDir() + "/baseline.json"
+
+	cmd := exec.Command("./"+testBinaryName, "baseline", "--config", "testdata/.drift.replay.yaml", "--output", baseline)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("baseline failed: %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "Wrote 1 baseline entries for 1 out-of-sync rules") {
+		t.Errorf("unexpected output:\n%s", string(output))
+	}
+
+	// The recorded drift is accepted by the baseline.
+	cmd = exec.Command("./"+testBinaryName, "check", "--config", "testdata/.drift.replay.yaml", "--baseline", baseline)
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("expected the baselined check to pass, got %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "Result: Out of Sync, accepted by the baseline") {
+		t.Errorf("expected the drift to be accepted, got:\n%s", string(output))
+	}
+
+	// Baseline entries of rules that are now in sync can be pruned.
+	data, err := os.ReadFile(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := strings.Replace(string(data), `"rule": "Missing Parameter in Docs"`, `"rule": "In Sync Example"`, 1)
+	if err := os.WriteFile(baseline, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = exec.Command("./"+testBinaryName, "check", "--config", "testdata/.drift.replay.yaml", "--baseline", baseline)
+	output, err = cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1 for drift missing from the baseline, got %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "1 baseline entries are fixed and can be pruned from "+baseline) {
+		t.Errorf("expected the stale entry to be reported, got:\n%s", string(output))
+	}
+}
+
+func TestBaselineCommand_Errors(t *testing.T) {
+	baseline := t.TempDir() + "/baseline.json"
+
+	cmd := exec.Command("./"+testBinaryName, "baseline", "--config", "testdata/.drift.scripted.yaml", "--rule-timeout", "200ms", "--output", baseline)
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected exit code 3, got %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "2 rules could not be checked; no baseline was written") {
+		t.Errorf("unexpected output:\n%s", string(output))
+	}
+	if _, err := os.Stat(baseline); !os.IsNotExist(err) {
+		t.Errorf("expected no baseline to be written, got %v", err)
+	}
+}
+
+func TestCheckCommand_ConsensusProvider(t *testing.T) {
+	cmd := exec.Command("./"+testBinaryName, "check", "--config", "testdata/.drift.consensus.yaml", "--no-cache")
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1 for drift, got %v\nOutput:\n%s", err, string(output))
+	}
+
+	for _, want := range []string{
+		"Result: Out of Sync (The `age` parameter of `updateUser` is not documented.)",
+		"[missing_param]",
+		"Dissent: optimist found it in sync",
+	} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, string(output))
+		}
+	}
+}
+
+func TestCheckCommand_ReplayProvider(t *testing.T) {
+	cmd := exec.Command("./"+testBinaryName, "check", "--config", "testdata/.drift.replay.yaml", "--format", "json")
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1 for drift, got %v\nOutput:\n%s", err, string(output))
+	}
+
+	var rep struct {
+		Rules []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Result struct {
+				Reason   string `json:"reason"`
+				Findings []struct {
+					DocFile  string `json:"doc_file"`
+					Line     int    `json:"line"`
+					Category string `json:"category"`
+				} `json:"findings"`
+			} `json:"result"`
+		} `json:"rules"`
+	}
+	if err := json.Unmarshal(output, &rep); err != nil {
+		t.Fatalf("output is not valid JSON: %v\nOutput:\n%s", err, string(output))
+	}
+	if len(rep.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %+v", rep.Rules)
+	}
+
+	drift := rep.Rules[0]
+	if drift.Status != "out_of_sync" || !strings.Contains(drift.Result.Reason, "age") {
+		t.Errorf("expected the missing parameter to be reported, got %+v", drift)
+	}
+	if len(drift.Result.Findings) != 1 || drift.Result.Findings[0].Category != "missing_param" || drift.Result.Findings[0].Line != 6 {
+		t.Errorf("unexpected findings: %+v", drift.Result.Findings)
+	}
+	if rep.Rules[1].Status != "in_sync" {
+		t.Errorf("expected the second rule to be in sync, got %+v", rep.Rules[1])
+	}
+}
+
+func TestCheckCommand_ReplayProvider_MissingFixture(t *testing.T) {
+	config := `version: 1
+provider: replay
+provider_options:
+  fixtures: testdata/fixtures
+rules:
+  - name: Unrecorded
+    code:
+      - testdata/src/api/user.go
+    docs:
+      - testdata/docs/api/users.md
+`
+	configPath := t.TempDir() + "/.drift.yaml"
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("./"+testBinaryName, "check", "--config", configPath)
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected exit code 3 for a missing recording, got %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "no recorded response") {
+		t.Errorf("expected a missing recording error, got:\n%s", string(output))
+	}
+}
+
+func TestCheckCommand_RecordAndReplay(t *testing.T) {
+	// A stand-in for an OpenAI-compatible server, whose answer is recorded
+	// and then replayed without it.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		answer := `{"is_in_sync": false, "reason": "The docs are stale.", "findings": []}`
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{
+				"index":         0,
+				"message":       map[string]string{"role": "assistant", "content": answer},
+				"finish_reason": "stop",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	fixtures := dir + "/fixtures"
+	rules := `rules:
+  - name: Users
+    code:
+      - testdata/src/api/user.go
+    docs:
+      - testdata/docs/api/users.md
+`
+	recordConfig := dir + "/record.yaml"
+	if err := os.WriteFile(recordConfig, []byte("version: 1\nprovider: openai-compatible\nprovider_options:\n  model: test\n  base_url: "+server.URL+"/v1\n"+rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	replayConfig := dir + "/replay.yaml"
+	if err := os.WriteFile(replayConfig, []byte("version: 1\nprovider: replay\nprovider_options:\n  fixtures: "+fixtures+"\n"+rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first check fills the cache, which --record must bypass to reach
+	// the server.
+	cacheDir := dir + "/cache"
+	for _, run := range []struct {
+		name string
+		args []string
+	}{
+		{name: "check", args: []string{"check", "--config", recordConfig, "--cache-dir", cacheDir}},
+		{name: "record", args: []string{"check", "--config", recordConfig, "--cache-dir", cacheDir, "--record", fixtures}},
+		{name: "replay", args: []string{"check", "--config", replayConfig}},
+	} {
+		if run.name == "replay" {
+			server.Close()
+		}
+		output, err := exec.Command("./"+testBinaryName, run.args...).CombinedOutput()
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Fatalf("%s: expected exit code 1 for drift, got %v\nOutput:\n%s", run.name, err, string(output))
+		}
+		if !strings.Contains(string(output), "Out of Sync (The docs are stale.)") {
+			t.Errorf("%s: expected the recorded drift, got:\n%s", run.name, string(output))
+		}
+	}
+}
+
+func TestCheckCommand_RecordDummyProvider(t *testing.T) {
+	cmd := exec.Command("./"+testBinaryName, "check", "--config", "testdata/.drift.severity.yaml", "--record", t.TempDir())
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("expected exit code 2, got %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "can't be recorded") {
+		t.Errorf("expected an error about recording, got:\n%s", string(output))
+	}
+}
+
+func TestCheckCommand_ScriptedDummyProvider(t *testing.T) {
+	const (
+		config     = "testdata/.drift.scripted.yaml"
+		inSyncCode = "testdata/src/api/user.go"
+		brokenCode = "testdata/e2e/true_negatives/in_sync_example/code.go"
+		unsureCode = "testdata/e2e/false_negatives/subtle_drift_example/code.go"
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "every outcome",
+			args:     []string{"--rule-timeout", "200ms"},
+			wantCode: 1,
+			want:     []string{"Result: In Sync", "[missing_param]", "Error: failed to assess drift: provider unavailable", "deadline exceeded", "Warning: Possibly Out of Sync", "Drift detected."},
+		},
+		{
+			name:     "low-confidence drift only",
+			args:     []string{"--changed-files", unsureCode},
+			wantCode: 0,
+			want:     []string{"Confidence 0.30 is below the minimum of 0.50.", "1 rules have low-confidence drift."},
+		},
+		{
+			name:     "in sync only",
+			args:     []string{"--changed-files", inSyncCode},
+			wantCode: 0,
+			want:     []string{"Result: In Sync"},
+		},
+		{
+			name:     "error only",
+			args:     []string{"--changed-files", brokenCode},
+			wantCode: 3,
+			want:     []string{"provider unavailable", "1 rules could not be checked."},
+		},
+		{
+			name:     "error only without failing on errors",
+			args:     []string{"--changed-files", brokenCode, "--fail-on-error=false"},
+			wantCode: 0,
+		},
+		{
+			name:     "junit",
+			args:     []string{"--rule-timeout", "200ms", "--format", "junit"},
+			wantCode: 1,
+			want:     []string{`<testsuites name="drift" tests="5" failures="1" errors="2"`, `<failure message="The `},
+		},
+		{
+			name:     "sarif",
+			args:     []string{"--rule-timeout", "200ms", "--format", "sarif"},
+			wantCode: 1,
+			want:     []string{`"ruleId": "drift/drift"`, `"startLine": 6`, `"executionSuccessful": false`, `"level": "warning"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("./"+testBinaryName, append([]string{"check", "--config", config}, tt.args...)...)
+			output, err := cmd.CombinedOutput()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("failed to run check: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d\nOutput:\n%s", code, tt.wantCode, string(output))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, string(output))
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCommand_FailOn(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{name: "default", args: nil, wantCode: 0, want: []string{"Severity: info", "Drift detected below --fail-on error"}},
+		{name: "warning", args: []string{"--fail-on", "warning"}, wantCode: 0, want: []string{"Severity: info", "Drift detected below --fail-on warning"}},
+		{name: "info", args: []string{"--fail-on", "info"}, wantCode: 1, want: []string{"Drift detected."}},
+		{name: "unknown", args: []string{"--fail-on", "critical"}, wantCode: 2, want: []string{`unknown severity "critical"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("./"+testBinaryName, append([]string{"check", "--config", "testdata/.drift.severity.yaml"}, tt.args...)...)
+			output, err := cmd.CombinedOutput()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("failed to run check: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d\nOutput:\n%s", code, tt.wantCode, string(output))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, string(output))
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCommand_Suppressions(t *testing.T) {
+	// Expired and malformed suppressions fail the check even when errors
+	// don't.
+	for _, args := range [][]string{nil, {"--fail-on-error=false"}} {
+		t.Run(strings.Join(append([]string{"default"}, args...), " "), func(t *testing.T) {
+			cmd := exec.Command("./"+testBinaryName, append([]string{"check", "--config", "testdata/.drift.suppress.yaml"}, args...)...)
+			output, err := cmd.CombinedOutput()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+				t.Fatalf("expected exit code 2 for the invalid suppressions, got %v\nOutput:\n%s", err, string(output))
+			}
+			for _, want := range []string{
+				"Ignored 2 regions of the docs marked with drift:ignore",
+				"testdata/docs/suppress/expired.md:3: suppression expired on 2020-01-01",
+				`testdata/docs/suppress/malformed.md:3: unexpected "until=2020-01-01" in drift:ignore marker`,
+			} {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("expected output to contain %q, got:\n%s", want, string(output))
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCommand_ExitCodes(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "")
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "missing config file", args: []string{"check", "--config", "testdata/does-not-exist.yaml"}, want: 2},
+		{name: "missing api key", args: []string{"check", "--config", "testdata/.drift.test.yaml"}, want: 2},
+		{name: "unknown flag", args: []string{"check", "--no-such-flag"}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command("./"+testBinaryName, tt.args...)
+			output, err := cmd.CombinedOutput()
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("expected the command to exit with an error, got %v\nOutput:\n%s", err, string(output))
+			}
+			if exitErr.ExitCode() != tt.want {
+				t.Errorf("exit code = %d, want %d\nOutput:\n%s", exitErr.ExitCode(), tt.want, string(output))
+			}
+		})
+	}
+}
+
+func TestCacheCommand_CacheDir(t *testing.T) {
+	// drift cache takes the same --cache-dir flag as drift check.
+	dir := t.TempDir()
+	output, err := exec.Command("./"+testBinaryName, "cache", "stats", "--cache-dir", dir).CombinedOutput()
+	if err != nil {
+		t.Fatalf("cache stats failed: %v\nOutput:\n%s", err, string(output))
+	}
+	if !strings.Contains(string(output), "Cache directory: "+dir) {
+		t.Errorf("expected the stats of %s, got:\n%s", dir, string(output))
+	}
 }
\ No newline at end of file
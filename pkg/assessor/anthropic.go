@@ -0,0 +1,178 @@
+package assessor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+const (
+	anthropicDefaultBaseURL = "https://api.anthropic.com"
+	anthropicDefaultModel   = "claude-sonnet-4-5"
+	anthropicAPIVersion     = "2023-06-01"
+	anthropicMaxTokens      = 4096
+)
+
+// AnthropicAssessor uses the Anthropic Messages API to assess drift.
+type AnthropicAssessor struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	maxTokens   int
+	temperature *float32
+	timeout     time.Duration
+	httpClient  *http.Client
+}
+
+// NewAnthropicAssessor creates a new AnthropicAssessor.
+// It reads the API key from the ANTHROPIC_API_KEY environment variable. The
+// model is taken from opts.Model, then ANTHROPIC_MODEL, and defaults to
+// claude-sonnet-4-5; ANTHROPIC_BASE_URL overrides the API endpoint. The
+// Messages API has no seed parameter, so opts.Seed is ignored.
+func NewAnthropicAssessor(opts config.ProviderOptions) (*AnthropicAssessor, error) {
+	return newAnthropicAssessor(opts, nil)
+}
+
+func newAnthropicAssessor(opts config.ProviderOptions, h *providerHTTP) (*AnthropicAssessor, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" && h.needsKey() {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable not set")
+	}
+
+	baseURL := os.Getenv("ANTHROPIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = anthropicDefaultBaseURL
+	}
+
+	model := opts.Model
+	if model == "" {
+		model = os.Getenv("ANTHROPIC_MODEL")
+	}
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+
+	maxTokens := opts.MaxOutputTokens
+	if maxTokens <= 0 {
+		maxTokens = anthropicMaxTokens
+	}
+
+	httpClient := http.DefaultClient
+	if client := h.client(); client != nil {
+		httpClient = client
+	}
+
+	return &AnthropicAssessor{
+		apiKey:      apiKey,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		model:       model,
+		maxTokens:   maxTokens,
+		temperature: opts.Temperature,
+		timeout:     opts.Timeout,
+		httpClient:  httpClient,
+	}, nil
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float32           `json:"temperature,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+}
+
+type anthropicErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Assess uses the Anthropic API to assess drift between code and documentation.
+func (a *AnthropicAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	ctx, cancel := withTimeout(ctx, a.timeout)
+	defer cancel()
+
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(anthropicRequest{
+		Model:       a.model,
+		MaxTokens:   a.maxTokens,
+		Temperature: a.temperature,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("content-type", "application/json")
+	httpReq.Header.Set("x-api-key", a.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := a.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Anthropic API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Message:    "anthropic API error",
+		}
+		var errResp anthropicErrorResponse
+		if err := json.Unmarshal(respBody, &errResp); err == nil && errResp.Error.Message != "" {
+			apiErr.Message += ": " + errResp.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	var msg anthropicResponse
+	if err := json.Unmarshal(respBody, &msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	for _, block := range msg.Content {
+		if block.Type == "text" {
+			return parseAssessmentResult(block.Text)
+		}
+	}
+
+	// An answer without text didn't assess anything, so it isn't drift.
+	return nil, fmt.Errorf("anthropic response has no text content (stop reason: %s)", msg.StopReason)
+}
@@ -0,0 +1,197 @@
+package assessor_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// newAnthropicServer starts a stand-in for the Anthropic Messages API that
+// answers every request with the given status code and body.
+func newAnthropicServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("x-api-key"); got != "test-key" {
+			t.Errorf("x-api-key = %q, want %q", got, "test-key")
+		}
+		if r.Header.Get("anthropic-version") == "" {
+			t.Errorf("anthropic-version header not set")
+		}
+
+		var req struct {
+			Model     string `json:"model"`
+			MaxTokens int    `json:"max_tokens"`
+			Messages  []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Model != "claude-test" {
+			t.Errorf("model = %q, want %q", req.Model, "claude-test")
+		}
+		// Answers with several findings run well past 1024 tokens.
+		if req.MaxTokens != 4096 {
+			t.Errorf("max_tokens = %d, want 4096", req.MaxTokens)
+		}
+		if len(req.Messages) != 1 || !strings.Contains(req.Messages[0].Content, "func GetUser") {
+			t.Errorf("prompt does not contain the code: %+v", req.Messages)
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// anthropicText builds a Messages API response with a single text block.
+func anthropicText(text string) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"content": []map[string]string{{"type": "text", "text": text}},
+	})
+	return string(data)
+}
+
+func setupAnthropicEnv(t *testing.T, baseURL string) {
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv("ANTHROPIC_BASE_URL", baseURL)
+	t.Setenv("ANTHROPIC_MODEL", "claude-test")
+}
+
+func TestAnthropicAssessor_Assess(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantErr    bool
+		wantInSync bool
+		wantReason string
+		wantFinds  []assessor.Finding
+	}{
+		{
+			name:       "in sync",
+			status:     http.StatusOK,
+			body:       anthropicText(`{"is_in_sync": true, "reason": ""}`),
+			wantInSync: true,
+		},
+		{
+			name:       "out of sync in code fence",
+			status:     http.StatusOK,
+			body:       anthropicText("```json\n{\"is_in_sync\": false, \"reason\": \"id is undocumented\"}\n```"),
+			wantInSync: false,
+			wantReason: "id is undocumented",
+		},
+		{
+			name:   "out of sync with findings",
+			status: http.StatusOK,
+			body: anthropicText(`{"is_in_sync": false, "reason": "id is undocumented", "findings": [
+				{"doc_file": "users.md", "line": 5, "section": "Parameters", "code_file": "user.go", "symbol": "GetUser",
+				 "category": "missing_param", "description": "id is not documented", "suggestion": "- id (int): The ID of the user."}
+			]}`),
+			wantInSync: false,
+			wantReason: "id is undocumented",
+			wantFinds: []assessor.Finding{{
+				DocFile:     "users.md",
+				Line:        5,
+				Section:     "Parameters",
+				CodeFile:    "user.go",
+				Symbol:      "GetUser",
+				Category:    assessor.CategoryMissingParam,
+				Description: "id is not documented",
+				Suggestion:  "- id (int): The ID of the user.",
+			}},
+		},
+		{
+			name:    "api error",
+			status:  http.StatusTooManyRequests,
+			body:    `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`,
+			wantErr: true,
+		},
+		{
+			name:    "no text content",
+			status:  http.StatusOK,
+			body:    `{"content": [], "stop_reason": "refusal"}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed answer",
+			status:  http.StatusOK,
+			body:    anthropicText("I think it is fine."),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAnthropicServer(t, tt.status, tt.body)
+			setupAnthropicEnv(t, server.URL)
+
+			a, err := assessor.NewAnthropicAssessor(config.ProviderOptions{})
+			if err != nil {
+				t.Fatalf("NewAnthropicAssessor() error = %v", err)
+			}
+
+			got, err := a.Assess(context.Background(), assessor.Request{
+				DocContent:   "# GetUser",
+				CodeContents: map[string]string{"user.go": "func GetUser(id int) {}"},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Assess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.IsInSync != tt.wantInSync {
+				t.Errorf("IsInSync = %v, want %v", got.IsInSync, tt.wantInSync)
+			}
+			if got.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.wantReason)
+			}
+			if !reflect.DeepEqual(got.Findings, tt.wantFinds) {
+				t.Errorf("Findings = %+v, want %+v", got.Findings, tt.wantFinds)
+			}
+		})
+	}
+}
+
+func TestAnthropicAssessor_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`)
+	}))
+	t.Cleanup(server.Close)
+	setupAnthropicEnv(t, server.URL)
+
+	a, err := assessor.NewAnthropicAssessor(config.ProviderOptions{})
+	if err != nil {
+		t.Fatalf("NewAnthropicAssessor() error = %v", err)
+	}
+
+	_, err = a.Assess(context.Background(), assessor.Request{DocContent: "# GetUser", CodeContents: nil})
+	var apiErr *assessor.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Assess() error = %v, want an *assessor.APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.RetryAfter != 5*time.Second {
+		t.Errorf("APIError = %+v, want status 429 and a 5s Retry-After", apiErr)
+	}
+	if !strings.Contains(apiErr.Error(), "slow down") {
+		t.Errorf("Error() = %q, want it to include the API message", apiErr.Error())
+	}
+}
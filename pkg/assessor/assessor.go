@@ -1,28 +1,148 @@
 package assessor
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
 // AssessmentResult holds the result of a drift assessment.
 type AssessmentResult struct {
 	IsInSync bool   `json:"is_in_sync"`
 	Reason   string `json:"reason"`
+	// Confidence is the model's confidence in the verdict, from 0 to 1, or
+	// nil if the model didn't say.
+	Confidence *float64  `json:"confidence,omitempty"`
+	Findings   []Finding `json:"findings,omitempty"`
+	// Dissent lists the votes that disagreed with the verdict of the
+	// consensus provider.
+	Dissent []Dissent `json:"dissent,omitempty"`
+}
+
+// Dissent is a vote of the consensus provider that disagreed with the
+// verdict, or a voter that failed to vote.
+type Dissent struct {
+	Voter    string `json:"voter"`
+	IsInSync bool   `json:"is_in_sync"`
+	Reason   string `json:"reason,omitempty"`
+	// Error is set when the voter failed to vote.
+	Error string `json:"error,omitempty"`
+}
+
+// Finding categories.
+const (
+	CategoryMissingParam    = "missing_param"
+	CategoryWrongType       = "wrong_type"
+	CategoryRemovedEndpoint = "removed_endpoint"
+	CategoryStaleExample    = "stale_example"
+	CategoryWrongBehavior   = "wrong_behavior"
+	CategoryUndocumented    = "undocumented"
+	CategoryOther           = "other"
+)
+
+// Categories lists every finding category a provider may report.
+var Categories = []string{
+	CategoryMissingParam,
+	CategoryWrongType,
+	CategoryRemovedEndpoint,
+	CategoryStaleExample,
+	CategoryWrongBehavior,
+	CategoryUndocumented,
+	CategoryOther,
+}
+
+// Finding is a single discrepancy between the documentation and the code.
+type Finding struct {
+	// DocFile is the documentation file that is out of date.
+	DocFile string `json:"doc_file,omitempty"`
+	// Line is the approximate line in DocFile, or 0 if unknown.
+	Line int `json:"line,omitempty"`
+	// Section is the heading of the affected part of DocFile.
+	Section string `json:"section,omitempty"`
+	// CodeFile and Symbol identify the code the documentation disagrees with.
+	CodeFile string `json:"code_file,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	// Category is one of Categories.
+	Category string `json:"category"`
+	// Description explains the discrepancy.
+	Description string `json:"description"`
+	// Suggestion is a proposed correction to the documentation.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// PromptVersion identifies the prompts sent to providers. Bump it whenever
+// the prompts change, so that cached assessments made with the old prompts
+// are no longer used.
+const PromptVersion = "3"
+
+// findingsInstructions describes the findings format to models that can't be
+// given a response schema.
+var findingsInstructions = `"findings" is a list with one object per discrepancy, and is empty when the documentation is in sync. Each finding has the fields:
+- "doc_file": the documentation file that is out of date
+- "line": the approximate line number in the documentation file, or 0 if unknown
+- "section": the heading of the affected documentation section
+- "code_file": the code file the documentation disagrees with
+- "symbol": the function, type, parameter or endpoint concerned
+- "category": one of ` + strings.Join(Categories, ", ") + `
+- "description": what is wrong
+- "suggestion": the corrected documentation text`
+
+// Request is the input of an assessment.
+type Request struct {
+	// Rule is the rule being checked.
+	Rule config.Rule
+	// DocContent is the content of the rule's doc files.
+	DocContent string
+	// CodeContents maps the path of each of the rule's code files to its
+	// content.
+	CodeContents map[string]string
+	// Prompt renders the prompt for the request. Nil means DefaultPrompt.
+	Prompt *Prompt
+}
+
+// RenderPrompt renders the prompt to send to a provider for the request.
+func (r Request) RenderPrompt() (string, error) {
+	p := r.Prompt
+	if p == nil {
+		p = DefaultPrompt()
+	}
+	return p.Render(r.Rule, r.DocContent, r.CodeContents)
 }
 
 // DocAssessor is the interface for assessing drift between code and documentation.
+// Implementations must be safe for concurrent use, since rules are checked in
+// parallel, and must return promptly once ctx is done.
 type DocAssessor interface {
-	Assess(docContent string, codeContents map[string]string) (*AssessmentResult, error)
+	Assess(ctx context.Context, req Request) (*AssessmentResult, error)
 }
 
-// DummyAssessor is a mock assessor for testing purposes.
-type DummyAssessor struct{}
+// parseAssessmentResult unmarshals a model's JSON answer into an
+// AssessmentResult. Models that don't support structured output sometimes wrap
+// the JSON in a Markdown code fence, so that is stripped first.
+func parseAssessmentResult(text string) (*AssessmentResult, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(text, "```")
+		text = strings.TrimSpace(text)
+	}
 
-// NewDummyAssessor creates a new DummyAssessor.
-func NewDummyAssessor() *DummyAssessor {
-	return &DummyAssessor{}
+	var result AssessmentResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal assessment result: %w", err)
+	}
+	return &result, nil
 }
 
-// Assess returns a hardcoded assessment result.
-func (a *DummyAssessor) Assess(docContent string, codeContents map[string]string) (*AssessmentResult, error) {
-	return &AssessmentResult{
-		IsInSync: true,
-		Reason:   "This is a dummy assessment.",
-	}, nil
+// withTimeout bounds ctx by the configured request timeout, if one is set.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
 }
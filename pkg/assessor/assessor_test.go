@@ -4,12 +4,14 @@ import (
 	"testing"
 
 	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
 )
 
 func TestNewAssessor(t *testing.T) {
 	tests := []struct {
 		name     string
 		provider string
+		opts     config.ProviderOptions
 		wantErr  bool
 		wantType interface{} // Expected type of the returned assessor
 	}{
@@ -19,12 +21,92 @@ func TestNewAssessor(t *testing.T) {
 			wantErr:  true,
 			wantType: nil,
 		},
+		{
+			name:     "Anthropic provider - no api key",
+			provider: "anthropic",
+			wantErr:  true,
+			wantType: nil,
+		},
+		{
+			name:     "OpenAI-compatible provider",
+			provider: "openai-compatible",
+			opts:     config.ProviderOptions{BaseURL: "http://localhost:8000/v1", Model: "llama3"},
+			wantErr:  false,
+			wantType: &assessor.OpenAIAssessor{},
+		},
+		{
+			name:     "OpenAI-compatible provider - no base url",
+			provider: "openai-compatible",
+			opts:     config.ProviderOptions{Model: "llama3"},
+			wantErr:  true,
+			wantType: nil,
+		},
+		{
+			name:     "Ollama provider - default base url",
+			provider: "ollama",
+			opts:     config.ProviderOptions{Model: "llama3"},
+			wantErr:  false,
+			wantType: &assessor.OpenAIAssessor{},
+		},
+		{
+			name:     "Ollama provider - no model",
+			provider: "ollama",
+			wantErr:  true,
+			wantType: nil,
+		},
+		{
+			name:     "Replay provider",
+			provider: "replay",
+			opts:     config.ProviderOptions{Fixtures: "testdata/fixtures"},
+			wantErr:  false,
+			wantType: &assessor.ReplayAssessor{},
+		},
+		{
+			name:     "Replay provider - no fixtures",
+			provider: "replay",
+			wantErr:  true,
+			wantType: nil,
+		},
 		{
 			name:     "Dummy provider",
 			provider: "dummy",
 			wantErr:  false,
 			wantType: &assessor.DummyAssessor{},
 		},
+		{
+			name:     "Consensus provider",
+			provider: "consensus",
+			opts: config.ProviderOptions{Consensus: &config.ConsensusOptions{
+				Voters: []config.Voter{{Provider: "dummy", Samples: 3}, {Provider: "dummy"}},
+			}},
+			wantErr:  false,
+			wantType: &assessor.ConsensusAssessor{},
+		},
+		{
+			name:     "Consensus provider - no voters",
+			provider: "consensus",
+			wantErr:  true,
+			wantType: nil,
+		},
+		{
+			name:     "Consensus provider - nested consensus",
+			provider: "consensus",
+			opts: config.ProviderOptions{Consensus: &config.ConsensusOptions{
+				Voters: []config.Voter{{Provider: "consensus"}},
+			}},
+			wantErr:  true,
+			wantType: nil,
+		},
+		{
+			name:     "Consensus provider - unknown strategy",
+			provider: "consensus",
+			opts: config.ProviderOptions{Consensus: &config.ConsensusOptions{
+				Strategy: "plurality",
+				Voters:   []config.Voter{{Provider: "dummy"}},
+			}},
+			wantErr:  true,
+			wantType: nil,
+		},
 		{
 			name:     "Unknown provider",
 			provider: "unknown",
@@ -38,8 +120,11 @@ func TestNewAssessor(t *testing.T) {
 			if tt.name == "Gemini provider - no api key" {
 				t.Setenv("GEMINI_API_KEY", "")
 			}
+			if tt.name == "Anthropic provider - no api key" {
+				t.Setenv("ANTHROPIC_API_KEY", "")
+			}
 
-			got, err := assessor.New(tt.provider)
+			got, err := assessor.New(tt.provider, tt.opts)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
@@ -53,6 +138,14 @@ func TestNewAssessor(t *testing.T) {
 					if _, ok := got.(*assessor.GeminiAssessor); !ok {
 						t.Errorf("New() got = %T, want %T", got, tt.wantType)
 					}
+				} else if _, ok := tt.wantType.(*assessor.OpenAIAssessor); ok {
+					if _, ok := got.(*assessor.OpenAIAssessor); !ok {
+						t.Errorf("New() got = %T, want %T", got, tt.wantType)
+					}
+				} else if _, ok := tt.wantType.(*assessor.ReplayAssessor); ok {
+					if _, ok := got.(*assessor.ReplayAssessor); !ok {
+						t.Errorf("New() got = %T, want %T", got, tt.wantType)
+					}
 				} else if _, ok := tt.wantType.(*assessor.DummyAssessor); ok {
 					if _, ok := got.(*assessor.DummyAssessor); !ok {
 						t.Errorf("New() got = %T, want %T", got, tt.wantType)
@@ -0,0 +1,208 @@
+package assessor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// charsPerToken is the average number of characters per token of each
+// provider's tokenizer on a mix of code and prose. Providers not listed use
+// defaultCharsPerToken.
+var charsPerToken = map[string]float64{
+	"anthropic": 3.5,
+}
+
+const defaultCharsPerToken = 4.0
+
+// EstimateTokens returns a rough estimate of the number of tokens the given
+// provider's tokenizer produces for text. It errs on the high side and is
+// only meant for budgeting, not billing.
+func EstimateTokens(provider, text string) int {
+	cpt, ok := charsPerToken[provider]
+	if !ok {
+		cpt = defaultCharsPerToken
+	}
+	return int(math.Ceil(float64(len(text)) / cpt))
+}
+
+// TooLargeError is returned when a rule's prompt exceeds its token budget.
+type TooLargeError struct {
+	// Tokens is the estimated size of the prompt.
+	Tokens int
+	Budget int
+	// File is the code file that doesn't fit in a chunk on its own, when
+	// splitting failed.
+	File string
+	// DocsOnly is set when splitting failed because the documentation
+	// doesn't fit without any code.
+	DocsOnly bool
+}
+
+func (e *TooLargeError) Error() string {
+	if e.DocsOnly {
+		return fmt.Sprintf("rule is too large: the documentation alone takes about %d tokens, over the budget of %d", e.Tokens, e.Budget)
+	}
+	if e.File != "" {
+		return fmt.Sprintf("rule is too large: the documentation and %s alone take about %d tokens, over the budget of %d", e.File, e.Tokens, e.Budget)
+	}
+	return fmt.Sprintf("rule is too large: about %d tokens, over the budget of %d; narrow its globs, raise token_budget.max_tokens or set token_budget.oversize to %q", e.Tokens, e.Budget, config.OversizeSplit)
+}
+
+// BudgetedAssessor enforces a token budget on the prompts sent by the
+// DocAssessor it wraps. Rules over budget either fail or are split into
+// chunks of code files that are assessed separately.
+type BudgetedAssessor struct {
+	inner    DocAssessor
+	provider string
+	budget   config.TokenBudget
+}
+
+// NewBudgetedAssessor wraps inner, the assessor for provider, so that its
+// prompts stay within budget.
+func NewBudgetedAssessor(inner DocAssessor, provider string, budget config.TokenBudget) (*BudgetedAssessor, error) {
+	switch budget.Oversize {
+	case "":
+		budget.Oversize = config.OversizeFail
+	case config.OversizeFail, config.OversizeSplit:
+	default:
+		return nil, fmt.Errorf("unknown token_budget.oversize %q (supported: %s, %s)", budget.Oversize, config.OversizeFail, config.OversizeSplit)
+	}
+	if budget.MaxTokens < 0 {
+		return nil, fmt.Errorf("token_budget.max_tokens must be positive, got %d", budget.MaxTokens)
+	}
+	return &BudgetedAssessor{inner: inner, provider: provider, budget: budget}, nil
+}
+
+// Assess assesses the documentation against all the code at once when it fits
+// the budget, and otherwise fails or splits the code according to the
+// oversize strategy.
+func (a *BudgetedAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	if a.budget.MaxTokens == 0 {
+		return a.inner.Assess(ctx, req)
+	}
+
+	total, err := a.estimate(req, req.CodeContents)
+	if err != nil {
+		return nil, err
+	}
+	if total <= a.budget.MaxTokens {
+		return a.inner.Assess(ctx, req)
+	}
+	if a.budget.Oversize != config.OversizeSplit {
+		return nil, &TooLargeError{Tokens: total, Budget: a.budget.MaxTokens}
+	}
+
+	// Every chunk carries the whole documentation, so it must fit on its
+	// own. Each file is counted by how much it adds to the prompt.
+	docTokens, err := a.estimate(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	if docTokens > a.budget.MaxTokens {
+		return nil, &TooLargeError{Tokens: docTokens, Budget: a.budget.MaxTokens, DocsOnly: true}
+	}
+	paths := make([]string, 0, len(req.CodeContents))
+	fileTokens := make(map[string]int, len(req.CodeContents))
+	for path, content := range req.CodeContents {
+		tokens, err := a.estimate(req, map[string]string{path: content})
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		fileTokens[path] = tokens - docTokens
+	}
+	sort.Strings(paths)
+
+	// Pack the files, in path order, into as few chunks as fit next to the
+	// documentation.
+	var chunks []map[string]string
+	chunk, chunkTokens := map[string]string{}, docTokens
+	for _, path := range paths {
+		if docTokens+fileTokens[path] > a.budget.MaxTokens {
+			return nil, &TooLargeError{Tokens: docTokens + fileTokens[path], Budget: a.budget.MaxTokens, File: path}
+		}
+		if chunkTokens+fileTokens[path] > a.budget.MaxTokens {
+			chunks = append(chunks, chunk)
+			chunk, chunkTokens = map[string]string{}, docTokens
+		}
+		chunk[path] = req.CodeContents[path]
+		chunkTokens += fileTokens[path]
+	}
+	chunks = append(chunks, chunk)
+
+	results := make([]*AssessmentResult, 0, len(chunks))
+	for i, chunk := range chunks {
+		chunkReq := req
+		chunkReq.CodeContents = chunk
+		result, err := a.inner.Assess(ctx, chunkReq)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err)
+		}
+		results = append(results, result)
+	}
+	return mergeResults(results), nil
+}
+
+// estimate returns the estimated size of the prompt for req with the given
+// code files.
+func (a *BudgetedAssessor) estimate(req Request, code map[string]string) (int, error) {
+	req.CodeContents = code
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return 0, err
+	}
+	return EstimateTokens(a.provider, prompt), nil
+}
+
+// mergeResults combines the assessments of the chunks of a rule: the rule is
+// in sync only if every chunk is, and the reasons and findings of the chunks
+// that aren't are concatenated. The drift of a rule is as certain as that of
+// its most certain chunk, while being in sync is only as certain as the least
+// certain chunk. A chunk without a confidence counts as certain.
+func mergeResults(results []*AssessmentResult) *AssessmentResult {
+	merged := &AssessmentResult{IsInSync: true}
+	var reasons []string
+	var inSync, outOfSync []*float64
+	for _, r := range results {
+		if r.IsInSync {
+			inSync = append(inSync, r.Confidence)
+			continue
+		}
+		merged.IsInSync = false
+		outOfSync = append(outOfSync, r.Confidence)
+		if r.Reason != "" {
+			reasons = append(reasons, r.Reason)
+		}
+		merged.Findings = append(merged.Findings, r.Findings...)
+	}
+	merged.Reason = strings.Join(reasons, "; ")
+	if merged.IsInSync {
+		merged.Confidence = combineConfidence(inSync, math.Min)
+	} else {
+		merged.Confidence = combineConfidence(outOfSync, math.Max)
+	}
+	return merged
+}
+
+// combineConfidence folds confidences with combine, or returns nil if any of
+// them is unknown.
+func combineConfidence(confidences []*float64, combine func(x, y float64) float64) *float64 {
+	var combined *float64
+	for _, c := range confidences {
+		if c == nil {
+			return nil
+		}
+		if combined == nil {
+			v := *c
+			combined = &v
+		} else {
+			*combined = combine(*combined, *c)
+		}
+	}
+	return combined
+}
@@ -0,0 +1,242 @@
+package assessor_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// chunkRecorder records the code files of each assessment and reports every
+// chunk containing a file named "drift.go" as out of sync.
+type chunkRecorder struct {
+	chunks [][]string
+}
+
+func (r *chunkRecorder) Assess(ctx context.Context, req assessor.Request) (*assessor.AssessmentResult, error) {
+	var paths []string
+	for path := range req.CodeContents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	r.chunks = append(r.chunks, paths)
+
+	if _, ok := req.CodeContents["drift.go"]; ok {
+		return &assessor.AssessmentResult{
+			Reason:   "drift.go changed",
+			Findings: []assessor.Finding{{CodeFile: "drift.go", Category: assessor.CategoryWrongBehavior}},
+		}, nil
+	}
+	return &assessor.AssessmentResult{IsInSync: true}, nil
+}
+
+// fileOfTokens returns content estimated at n tokens.
+func fileOfTokens(n int) string {
+	return strings.Repeat("x", n*4)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	text := strings.Repeat("a", 35)
+	if got := assessor.EstimateTokens("gemini", text); got != 9 {
+		t.Errorf("EstimateTokens(gemini) = %d, want 9", got)
+	}
+	if got := assessor.EstimateTokens("anthropic", text); got != 10 {
+		t.Errorf("EstimateTokens(anthropic) = %d, want 10", got)
+	}
+}
+
+func TestBudgetedAssessor(t *testing.T) {
+	// With about 1000 tokens of docs and the prompt's instructions, each of
+	// these files fits next to the docs, but no two of a, b and c fit
+	// together.
+	code := map[string]string{
+		"a.go":     fileOfTokens(1500),
+		"b.go":     fileOfTokens(1500),
+		"c.go":     fileOfTokens(500),
+		"drift.go": fileOfTokens(100),
+	}
+
+	tests := []struct {
+		name       string
+		budget     config.TokenBudget
+		wantChunks [][]string
+		wantErr    bool
+	}{
+		{
+			name:       "no budget",
+			budget:     config.TokenBudget{},
+			wantChunks: [][]string{{"a.go", "b.go", "c.go", "drift.go"}},
+		},
+		{
+			name:       "within budget",
+			budget:     config.TokenBudget{MaxTokens: 10000},
+			wantChunks: [][]string{{"a.go", "b.go", "c.go", "drift.go"}},
+		},
+		{
+			name:    "over budget fails by default",
+			budget:  config.TokenBudget{MaxTokens: 3000},
+			wantErr: true,
+		},
+		{
+			name:       "over budget splits",
+			budget:     config.TokenBudget{MaxTokens: 3000, Oversize: config.OversizeSplit},
+			wantChunks: [][]string{{"a.go"}, {"b.go"}, {"c.go", "drift.go"}},
+		},
+		{
+			name:    "single file over budget",
+			budget:  config.TokenBudget{MaxTokens: 2000, Oversize: config.OversizeSplit},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &chunkRecorder{}
+			a, err := assessor.NewBudgetedAssessor(inner, "gemini", tt.budget)
+			if err != nil {
+				t.Fatalf("NewBudgetedAssessor() error = %v", err)
+			}
+
+			got, err := a.Assess(context.Background(), assessor.Request{DocContent: fileOfTokens(800), CodeContents: code})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Assess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var tooLarge *assessor.TooLargeError
+				if !errors.As(err, &tooLarge) {
+					t.Errorf("Assess() error = %v, want a *TooLargeError", err)
+				}
+				if len(inner.chunks) != 0 {
+					t.Errorf("expected no request, got %d", len(inner.chunks))
+				}
+				return
+			}
+			if !reflect.DeepEqual(inner.chunks, tt.wantChunks) {
+				t.Errorf("chunks = %v, want %v", inner.chunks, tt.wantChunks)
+			}
+			// Only the chunk with drift.go is out of sync; its verdict must
+			// survive the merge.
+			if got.IsInSync || got.Reason != "drift.go changed" || len(got.Findings) != 1 {
+				t.Errorf("Assess() = %+v, want the out-of-sync verdict for drift.go", got)
+			}
+		})
+	}
+}
+
+func TestBudgetedAssessor_DocsOverBudget(t *testing.T) {
+	budget := config.TokenBudget{MaxTokens: 1000, Oversize: config.OversizeSplit}
+	for name, code := range map[string]map[string]string{
+		"no code files": nil,
+		"small code":    {"a.go": "package a\n"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			inner := &chunkRecorder{}
+			a, err := assessor.NewBudgetedAssessor(inner, "gemini", budget)
+			if err != nil {
+				t.Fatalf("NewBudgetedAssessor() error = %v", err)
+			}
+
+			_, err = a.Assess(context.Background(), assessor.Request{DocContent: fileOfTokens(1200), CodeContents: code})
+			var tooLarge *assessor.TooLargeError
+			if !errors.As(err, &tooLarge) || !tooLarge.DocsOnly {
+				t.Errorf("Assess() error = %v, want a *TooLargeError for the docs alone", err)
+			}
+			if len(inner.chunks) != 0 {
+				t.Errorf("expected no request, got %d", len(inner.chunks))
+			}
+		})
+	}
+}
+
+func TestBudgetedAssessor_CountsPrompt(t *testing.T) {
+	// The docs and code fit the budget, but not with the prompt's
+	// instructions around them.
+	inner := &chunkRecorder{}
+	a, err := assessor.NewBudgetedAssessor(inner, "gemini", config.TokenBudget{MaxTokens: 1000})
+	if err != nil {
+		t.Fatalf("NewBudgetedAssessor() error = %v", err)
+	}
+	req := assessor.Request{DocContent: fileOfTokens(450), CodeContents: map[string]string{"a.go": fileOfTokens(450)}}
+	if _, err := a.Assess(context.Background(), req); err == nil {
+		t.Errorf("expected the rendered prompt to exceed the budget")
+	}
+	if len(inner.chunks) != 0 {
+		t.Errorf("expected no request, got %d", len(inner.chunks))
+	}
+}
+
+// scriptedChunks answers each chunk with the result scripted for its single
+// code file.
+type scriptedChunks map[string]*assessor.AssessmentResult
+
+func (s scriptedChunks) Assess(ctx context.Context, req assessor.Request) (*assessor.AssessmentResult, error) {
+	for path := range req.CodeContents {
+		return s[path], nil
+	}
+	return nil, errors.New("empty chunk")
+}
+
+func TestBudgetedAssessor_MergesConfidence(t *testing.T) {
+	confidence := func(c float64) *float64 { return &c }
+	inSync := func(c *float64) *assessor.AssessmentResult {
+		return &assessor.AssessmentResult{IsInSync: true, Confidence: c}
+	}
+	drift := func(c *float64) *assessor.AssessmentResult {
+		return &assessor.AssessmentResult{Reason: "drift", Confidence: c}
+	}
+
+	tests := []struct {
+		name   string
+		chunks scriptedChunks
+		want   *float64
+	}{
+		{
+			name:   "in sync takes the lowest confidence",
+			chunks: scriptedChunks{"a.go": inSync(confidence(0.9)), "b.go": inSync(confidence(0.6))},
+			want:   confidence(0.6),
+		},
+		{
+			name:   "drift takes the highest confidence of the drifted chunks",
+			chunks: scriptedChunks{"a.go": drift(confidence(0.4)), "b.go": drift(confidence(0.8)), "c.go": inSync(confidence(0.2))},
+			want:   confidence(0.8),
+		},
+		{
+			name:   "unknown confidence",
+			chunks: scriptedChunks{"a.go": drift(confidence(0.4)), "b.go": drift(nil)},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := map[string]string{}
+			for path := range tt.chunks {
+				code[path] = fileOfTokens(1500)
+			}
+			a, err := assessor.NewBudgetedAssessor(tt.chunks, "gemini", config.TokenBudget{MaxTokens: 3000, Oversize: config.OversizeSplit})
+			if err != nil {
+				t.Fatalf("NewBudgetedAssessor() error = %v", err)
+			}
+
+			got, err := a.Assess(context.Background(), assessor.Request{DocContent: "# Docs", CodeContents: code})
+			if err != nil {
+				t.Fatalf("Assess() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Confidence, tt.want) {
+				t.Errorf("Confidence = %v, want %v", got.Confidence, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBudgetedAssessor_InvalidStrategy(t *testing.T) {
+	_, err := assessor.NewBudgetedAssessor(assessor.NewDummyAssessor(), "gemini", config.TokenBudget{MaxTokens: 10, Oversize: "truncate"})
+	if err == nil {
+		t.Errorf("expected an error for an unknown oversize strategy")
+	}
+}
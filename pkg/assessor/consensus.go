@@ -0,0 +1,169 @@
+package assessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// Voter is a DocAssessor that votes in a consensus.
+type Voter struct {
+	// Name identifies the voter in dissenting votes.
+	Name     string
+	Assessor DocAssessor
+	// Weight is the weight of the voter's vote with the weighted strategy.
+	Weight float64
+}
+
+// ConsensusAssessor asks several voters to assess the same request and
+// combines their verdicts, to smooth out the variance of single answers.
+type ConsensusAssessor struct {
+	strategy string
+	voters   []Voter
+}
+
+// NewConsensusAssessor creates a ConsensusAssessor from opts.Consensus. Each
+// sample of a voter becomes a voter of its own. If wrap is not nil, it wraps
+// the assessor of each voter, e.g. to rate-limit and retry every request
+// rather than the consensus as a whole.
+func NewConsensusAssessor(opts config.ProviderOptions, wrap func(DocAssessor) DocAssessor) (*ConsensusAssessor, error) {
+	if opts.Consensus == nil || len(opts.Consensus.Voters) == 0 {
+		return nil, fmt.Errorf("provider_options.consensus.voters must be set for the consensus provider")
+	}
+
+	var voters []Voter
+	for i, v := range opts.Consensus.Voters {
+		if v.Provider == "consensus" {
+			return nil, fmt.Errorf("voter %d: the consensus provider can't vote in a consensus", i+1)
+		}
+		if v.Samples < 0 || v.Weight < 0 {
+			return nil, fmt.Errorf("voter %d: samples and weight must not be negative", i+1)
+		}
+		a, err := New(v.Provider, v.ProviderOptions)
+		if err != nil {
+			return nil, fmt.Errorf("voter %d: %w", i+1, err)
+		}
+		if wrap != nil {
+			a = wrap(a)
+		}
+
+		name := v.Name
+		if name == "" {
+			name = v.Provider
+			if v.ProviderOptions.Model != "" {
+				name += " (" + v.ProviderOptions.Model + ")"
+			}
+		}
+		samples := v.Samples
+		if samples == 0 {
+			samples = 1
+		}
+		weight := v.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		for s := 1; s <= samples; s++ {
+			voter := Voter{Name: name, Assessor: a, Weight: weight}
+			if samples > 1 {
+				voter.Name = fmt.Sprintf("%s #%d", name, s)
+			}
+			voters = append(voters, voter)
+		}
+	}
+	return newConsensusAssessor(opts.Consensus.Strategy, voters)
+}
+
+func newConsensusAssessor(strategy string, voters []Voter) (*ConsensusAssessor, error) {
+	switch strategy {
+	case "":
+		strategy = config.ConsensusMajority
+	case config.ConsensusMajority, config.ConsensusUnanimous, config.ConsensusWeighted:
+	default:
+		return nil, fmt.Errorf("unknown consensus strategy %q (supported: %s, %s, %s)",
+			strategy, config.ConsensusMajority, config.ConsensusUnanimous, config.ConsensusWeighted)
+	}
+	return &ConsensusAssessor{strategy: strategy, voters: voters}, nil
+}
+
+// vote is the answer of a single voter.
+type vote struct {
+	voter  Voter
+	result *AssessmentResult
+	err    error
+}
+
+// Assess asks every voter in parallel and combines their verdicts. Voters
+// that fail don't vote; the assessment only fails if every voter does. The
+// verdict takes its reason and findings from the first voter that agrees with
+// it, its confidence is the share of the votes that agree with it, and the
+// other votes are reported as dissent.
+func (a *ConsensusAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	votes := make([]vote, len(a.voters))
+	var wg sync.WaitGroup
+	for i, voter := range a.voters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := voter.Assessor.Assess(ctx, req)
+			votes[i] = vote{voter: voter, result: result, err: err}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var driftWeight, inSyncWeight float64
+	var errs []error
+	for _, v := range votes {
+		switch {
+		case v.err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", v.voter.Name, v.err))
+		case v.result.IsInSync:
+			inSyncWeight += a.weight(v.voter)
+		default:
+			driftWeight += a.weight(v.voter)
+		}
+	}
+	total := driftWeight + inSyncWeight
+	if total == 0 {
+		return nil, fmt.Errorf("every voter failed: %w", errors.Join(errs...))
+	}
+
+	inSync := driftWeight*2 < total
+	if a.strategy == config.ConsensusUnanimous {
+		inSync = inSyncWeight > 0
+	}
+	agreeing := inSyncWeight
+	if !inSync {
+		agreeing = driftWeight
+	}
+	confidence := agreeing / total
+
+	merged := &AssessmentResult{IsInSync: inSync, Confidence: &confidence}
+	representative := true
+	for _, v := range votes {
+		switch {
+		case v.err != nil:
+			merged.Dissent = append(merged.Dissent, Dissent{Voter: v.voter.Name, Error: v.err.Error()})
+		case v.result.IsInSync != inSync:
+			merged.Dissent = append(merged.Dissent, Dissent{Voter: v.voter.Name, IsInSync: v.result.IsInSync, Reason: v.result.Reason})
+		case representative:
+			merged.Reason = v.result.Reason
+			merged.Findings = v.result.Findings
+			representative = false
+		}
+	}
+	return merged, nil
+}
+
+// weight returns the weight of a voter's vote under the strategy.
+func (a *ConsensusAssessor) weight(voter Voter) float64 {
+	if a.strategy == config.ConsensusWeighted {
+		return voter.Weight
+	}
+	return 1
+}
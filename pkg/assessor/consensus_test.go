@@ -0,0 +1,200 @@
+package assessor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// ballot answers every request with the same result or error.
+type ballot struct {
+	result *AssessmentResult
+	err    error
+}
+
+func (b ballot) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	return b.result, b.err
+}
+
+func TestConsensusAssessor(t *testing.T) {
+	drift := func(reason string) DocAssessor {
+		return ballot{result: &AssessmentResult{Reason: reason, Findings: []Finding{{Category: CategoryOther, Description: reason}}}}
+	}
+	inSync := ballot{result: &AssessmentResult{IsInSync: true}}
+	failed := ballot{err: errors.New("overloaded")}
+
+	tests := []struct {
+		name           string
+		strategy       string
+		voters         []Voter
+		wantInSync     bool
+		wantReason     string
+		wantConfidence float64
+		wantDissent    []Dissent
+	}{
+		{
+			name:           "majority finds drift",
+			voters:         []Voter{{Name: "a", Assessor: inSync}, {Name: "b", Assessor: drift("b")}, {Name: "c", Assessor: drift("c")}},
+			wantReason:     "b",
+			wantConfidence: 2.0 / 3,
+			wantDissent:    []Dissent{{Voter: "a", IsInSync: true}},
+		},
+		{
+			name:           "majority in sync",
+			strategy:       config.ConsensusMajority,
+			voters:         []Voter{{Name: "a", Assessor: inSync}, {Name: "b", Assessor: drift("b")}, {Name: "c", Assessor: inSync}},
+			wantInSync:     true,
+			wantConfidence: 2.0 / 3,
+			wantDissent:    []Dissent{{Voter: "b", Reason: "b"}},
+		},
+		{
+			name:           "tie finds drift",
+			voters:         []Voter{{Name: "a", Assessor: inSync}, {Name: "b", Assessor: drift("b")}},
+			wantReason:     "b",
+			wantConfidence: 0.5,
+			wantDissent:    []Dissent{{Voter: "a", IsInSync: true}},
+		},
+		{
+			name:           "unanimous needs every vote",
+			strategy:       config.ConsensusUnanimous,
+			voters:         []Voter{{Name: "a", Assessor: drift("a")}, {Name: "b", Assessor: drift("b")}, {Name: "c", Assessor: inSync}},
+			wantInSync:     true,
+			wantConfidence: 1.0 / 3,
+			wantDissent:    []Dissent{{Voter: "a", Reason: "a"}, {Voter: "b", Reason: "b"}},
+		},
+		{
+			name:           "unanimous drift",
+			strategy:       config.ConsensusUnanimous,
+			voters:         []Voter{{Name: "a", Assessor: drift("a")}, {Name: "b", Assessor: drift("b")}},
+			wantReason:     "a",
+			wantConfidence: 1,
+		},
+		{
+			name:           "weighted",
+			strategy:       config.ConsensusWeighted,
+			voters:         []Voter{{Name: "a", Assessor: drift("a"), Weight: 3}, {Name: "b", Assessor: inSync, Weight: 1}, {Name: "c", Assessor: inSync, Weight: 1}},
+			wantReason:     "a",
+			wantConfidence: 0.6,
+			wantDissent:    []Dissent{{Voter: "b", IsInSync: true}, {Voter: "c", IsInSync: true}},
+		},
+		{
+			name:           "failed voters abstain",
+			voters:         []Voter{{Name: "a", Assessor: failed}, {Name: "b", Assessor: inSync}},
+			wantInSync:     true,
+			wantConfidence: 1,
+			wantDissent:    []Dissent{{Voter: "a", Error: "overloaded"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := newConsensusAssessor(tt.strategy, tt.voters)
+			if err != nil {
+				t.Fatalf("newConsensusAssessor() error = %v", err)
+			}
+			got, err := a.Assess(context.Background(), Request{})
+			if err != nil {
+				t.Fatalf("Assess() error = %v", err)
+			}
+			if got.IsInSync != tt.wantInSync || got.Reason != tt.wantReason {
+				t.Errorf("Assess() = %+v, want in sync %v with reason %q", got, tt.wantInSync, tt.wantReason)
+			}
+			if !tt.wantInSync && len(got.Findings) != 1 {
+				t.Errorf("expected the findings of one voter, got %+v", got.Findings)
+			}
+			if got.Confidence == nil || *got.Confidence != tt.wantConfidence {
+				t.Errorf("Confidence = %v, want %v", got.Confidence, tt.wantConfidence)
+			}
+			if !reflect.DeepEqual(got.Dissent, tt.wantDissent) {
+				t.Errorf("Dissent = %+v, want %+v", got.Dissent, tt.wantDissent)
+			}
+		})
+	}
+}
+
+func TestConsensusAssessor_EveryVoterFails(t *testing.T) {
+	errOverloaded := &APIError{StatusCode: 529, Message: "overloaded"}
+	a, err := newConsensusAssessor("", []Voter{
+		{Name: "a", Assessor: ballot{err: errOverloaded}},
+		{Name: "b", Assessor: ballot{err: errors.New("bad answer")}},
+	})
+	if err != nil {
+		t.Fatalf("newConsensusAssessor() error = %v", err)
+	}
+
+	_, err = a.Assess(context.Background(), Request{})
+	if err == nil || !strings.Contains(err.Error(), "every voter failed") {
+		t.Fatalf("Assess() error = %v, want every voter to fail", err)
+	}
+	// Retries still recognise the transient failures of the voters.
+	if ok, _ := retryable(err); !ok {
+		t.Errorf("expected %v to be retryable", err)
+	}
+}
+
+func TestNewConsensusAssessor_Samples(t *testing.T) {
+	a, err := NewConsensusAssessor(config.ProviderOptions{Consensus: &config.ConsensusOptions{
+		Voters: []config.Voter{
+			{Provider: "dummy", Samples: 2, Weight: 2},
+			{Name: "backup", Provider: "dummy"},
+		},
+	}}, nil)
+	if err != nil {
+		t.Fatalf("NewConsensusAssessor() error = %v", err)
+	}
+
+	var names []string
+	for _, v := range a.voters {
+		names = append(names, v.Name)
+	}
+	want := []string{"dummy #1", "dummy #2", "backup"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("voters = %v, want %v", names, want)
+	}
+	if a.voters[0].Weight != 2 || a.voters[2].Weight != 1 {
+		t.Errorf("unexpected weights: %+v", a.voters)
+	}
+}
+
+// countingAssessor counts the requests passed to the assessor it wraps.
+type countingAssessor struct {
+	DocAssessor
+	calls *int32
+}
+
+func (a countingAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	atomic.AddInt32(a.calls, 1)
+	return a.DocAssessor.Assess(ctx, req)
+}
+
+func TestNewConsensusAssessor_Wrap(t *testing.T) {
+	var wrapped, calls int32
+	wrap := func(a DocAssessor) DocAssessor {
+		wrapped++
+		return countingAssessor{DocAssessor: a, calls: &calls}
+	}
+	a, err := NewConsensusAssessor(config.ProviderOptions{Consensus: &config.ConsensusOptions{
+		Voters: []config.Voter{
+			{Provider: "dummy", Samples: 2},
+			{Name: "backup", Provider: "dummy"},
+		},
+	}}, wrap)
+	if err != nil {
+		t.Fatalf("NewConsensusAssessor() error = %v", err)
+	}
+	if wrapped != 2 {
+		t.Errorf("wrap called %d times, want once per voter", wrapped)
+	}
+
+	if _, err := a.Assess(context.Background(), Request{Rule: config.Rule{Name: "Users"}}); err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("wrapped assessors got %d requests, want one per sample", calls)
+	}
+}
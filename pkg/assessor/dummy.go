@@ -0,0 +1,85 @@
+package assessor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DummyAssessor is a mock assessor for testing purposes. It finds every rule
+// in sync, unless an outcome is scripted for the rule.
+type DummyAssessor struct {
+	// outcomes are the scripted outcomes loaded from a fixture file, by
+	// rule name.
+	outcomes map[string]config.DummyOutcome
+}
+
+// NewDummyAssessor creates a new DummyAssessor.
+func NewDummyAssessor() *DummyAssessor {
+	return &DummyAssessor{}
+}
+
+// NewScriptedDummyAssessor creates a DummyAssessor that also plays the
+// outcomes scripted in the YAML file opts.Fixtures, which maps rule names to
+// outcomes. Outcomes set on the rules themselves take precedence.
+func NewScriptedDummyAssessor(opts config.ProviderOptions) (*DummyAssessor, error) {
+	a := NewDummyAssessor()
+	if opts.Fixtures == "" {
+		return a, nil
+	}
+	data, err := os.ReadFile(opts.Fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dummy outcomes: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &a.outcomes); err != nil {
+		return nil, fmt.Errorf("failed to parse dummy outcomes %s: %w", opts.Fixtures, err)
+	}
+	return a, nil
+}
+
+// Assess plays the outcome scripted for the rule, or returns a hardcoded
+// in-sync result.
+func (a *DummyAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	outcome, ok := a.outcomes[req.Rule.Name]
+	if req.Rule.Dummy != nil {
+		outcome, ok = *req.Rule.Dummy, true
+	}
+	if !ok {
+		return &AssessmentResult{
+			IsInSync: true,
+			Reason:   "This is a dummy assessment.",
+		}, nil
+	}
+
+	if outcome.Delay > 0 {
+		if err := sleepContext(ctx, outcome.Delay); err != nil {
+			return nil, err
+		}
+	}
+	if outcome.Error != "" {
+		return nil, errors.New(outcome.Error)
+	}
+
+	result := &AssessmentResult{IsInSync: outcome.InSync, Reason: outcome.Reason, Confidence: outcome.Confidence}
+	if len(outcome.Findings) > 0 {
+		// The findings are written with the same field names as in a
+		// model's answer, so decode them the same way.
+		data, err := json.Marshal(outcome.Findings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scripted findings for rule '%s': %w", req.Rule.Name, err)
+		}
+		if err := json.Unmarshal(data, &result.Findings); err != nil {
+			return nil, fmt.Errorf("invalid scripted findings for rule '%s': %w", req.Rule.Name, err)
+		}
+	}
+	return result, nil
+}
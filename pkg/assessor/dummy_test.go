@@ -0,0 +1,119 @@
+package assessor_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+func TestDummyAssessor_Scripted(t *testing.T) {
+	fixtures := filepath.Join(t.TempDir(), "outcomes.yaml")
+	err := os.WriteFile(fixtures, []byte(`
+Users:
+  in_sync: false
+  reason: id is undocumented
+  findings:
+    - doc_file: docs/users.md
+      line: 4
+      category: missing_param
+      description: id is not documented
+Auth:
+  error: rate limited
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := assessor.New("dummy", config.ProviderOptions{Fixtures: fixtures})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		rule    config.Rule
+		want    *assessor.AssessmentResult
+		wantErr string
+	}{
+		{
+			name: "scripted drift",
+			rule: config.Rule{Name: "Users"},
+			want: &assessor.AssessmentResult{
+				Reason: "id is undocumented",
+				Findings: []assessor.Finding{{
+					DocFile:     "docs/users.md",
+					Line:        4,
+					Category:    assessor.CategoryMissingParam,
+					Description: "id is not documented",
+				}},
+			},
+		},
+		{
+			name:    "scripted error",
+			rule:    config.Rule{Name: "Auth"},
+			wantErr: "rate limited",
+		},
+		{
+			name: "outcome on the rule takes precedence",
+			rule: config.Rule{Name: "Auth", Dummy: &config.DummyOutcome{InSync: true, Reason: "fine"}},
+			want: &assessor.AssessmentResult{IsInSync: true, Reason: "fine"},
+		},
+		{
+			name: "unscripted rule",
+			rule: config.Rule{Name: "Other"},
+			want: &assessor.AssessmentResult{IsInSync: true, Reason: "This is a dummy assessment."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Assess(context.Background(), assessor.Request{Rule: tt.rule})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Assess() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Assess() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Assess() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDummyAssessor_Delay(t *testing.T) {
+	a := assessor.NewDummyAssessor()
+	rule := config.Rule{Name: "Slow", Dummy: &config.DummyOutcome{InSync: true, Delay: time.Hour}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := a.Assess(ctx, assessor.Request{Rule: rule}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Assess() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestDummyAssessor_InvalidFindings(t *testing.T) {
+	a := assessor.NewDummyAssessor()
+	rule := config.Rule{Name: "Bad", Dummy: &config.DummyOutcome{
+		Findings: []map[string]interface{}{{"line": "four"}},
+	}}
+	if _, err := a.Assess(context.Background(), assessor.Request{Rule: rule}); err == nil {
+		t.Errorf("expected an error for a finding with a non-numeric line")
+	}
+}
+
+func TestNewScriptedDummyAssessor_MissingFile(t *testing.T) {
+	if _, err := assessor.NewScriptedDummyAssessor(config.ProviderOptions{Fixtures: "does-not-exist.yaml"}); err == nil {
+		t.Errorf("expected an error for a missing outcomes file")
+	}
+}
@@ -2,17 +2,65 @@ package assessor
 
 import (
 	"fmt"
+	"net/http"
+
+	"github.com/driftee-ai/drift/pkg/config"
 )
 
-// New creates a new DocAssessor based on the provided provider name.
-func New(provider string) (DocAssessor, error) {
+// New creates a new DocAssessor based on the provided provider name and options.
+func New(provider string, opts config.ProviderOptions) (DocAssessor, error) {
+	switch provider {
+	case "consensus":
+		return NewConsensusAssessor(opts, nil)
+	case "replay":
+		return NewReplayAssessor(opts)
+	case "dummy":
+		return NewScriptedDummyAssessor(opts)
+	default:
+		return newAPIAssessor(provider, opts, nil)
+	}
+}
+
+// providerHTTP overrides how a provider sends its API requests, to record or
+// replay their responses.
+type providerHTTP struct {
+	transport http.RoundTripper
+	// offline means the responses are replayed, so no API key is needed.
+	offline bool
+}
+
+// client returns the HTTP client of a provider, or nil for the default one.
+func (h *providerHTTP) client() *http.Client {
+	if h == nil {
+		return nil
+	}
+	return &http.Client{Transport: h.transport}
+}
+
+// needsKey reports whether the provider needs its API key.
+func (h *providerHTTP) needsKey() bool {
+	return h == nil || !h.offline
+}
+
+// newAPIAssessor creates the assessor of a provider that calls an HTTP API.
+// If h is not nil, it overrides how the provider sends its requests.
+func newAPIAssessor(provider string, opts config.ProviderOptions, h *providerHTTP) (DocAssessor, error) {
 	switch provider {
 	case "gemini":
-		return NewGeminiAssessor()
+		return newGeminiAssessor(opts, h)
 	case "openai":
-		return NewOpenAIAssessor()
-	case "dummy":
-		return NewDummyAssessor(), nil
+		return newOpenAIAssessor(opts, h)
+	case "openai-compatible":
+		return newOpenAICompatibleAssessor(opts, h)
+	case "ollama":
+		if opts.BaseURL == "" {
+			opts.BaseURL = ollamaDefaultBaseURL
+		}
+		return newOpenAICompatibleAssessor(opts, h)
+	case "anthropic":
+		return newAnthropicAssessor(opts, h)
+	case "consensus", "replay", "dummy":
+		return nil, fmt.Errorf("the %s provider doesn't call an API, so its responses can't be recorded", provider)
 	default:
 		return nil, fmt.Errorf("unknown provider: %s", provider)
 	}
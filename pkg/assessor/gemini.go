@@ -4,39 +4,64 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/driftee-ai/drift/pkg/config"
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
 )
 
+const geminiDefaultModel = "gemini-2.5-flash"
+
 // GeminiAssessor uses the Gemini API to assess drift.
 type GeminiAssessor struct {
-	client *genai.GenerativeModel
+	client  *genai.GenerativeModel
+	timeout time.Duration
 }
 
 // NewGeminiAssessor creates a new GeminiAssessor.
 // It reads the Gemini API key from the GEMINI_API_KEY environment variable.
-func NewGeminiAssessor() (*GeminiAssessor, error) {
+// The Gemini SDK has no seed parameter, so opts.Seed is ignored.
+func NewGeminiAssessor(opts config.ProviderOptions) (*GeminiAssessor, error) {
+	return newGeminiAssessor(opts, nil)
+}
+
+func newGeminiAssessor(opts config.ProviderOptions, h *providerHTTP) (*GeminiAssessor, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
+		if h.needsKey() {
+			return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
+		}
+		// Replayed responses need no key, but the SDK requires one.
+		apiKey = "replay"
 	}
 
+	clientOpts := []option.ClientOption{option.WithAPIKey(apiKey)}
+	if client := h.client(); client != nil {
+		// The SDK doesn't add the API key to the requests of a custom
+		// client, so its transport does.
+		client.Transport = &geminiKeyTransport{key: apiKey, next: client.Transport}
+		clientOpts = append(clientOpts, option.WithHTTPClient(client))
+	}
 	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	client, err := genai.NewClient(ctx, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
 
-	model := client.GenerativeModel("gemini-2.5-flash")
-
-	return &GeminiAssessor{client: model}, nil
-}
-
-// Assess uses the Gemini API to assess drift between code and documentation.
-func (a *GeminiAssessor) Assess(docContent string, codeContents map[string]string) (*AssessmentResult, error) {
-	ctx := context.Background()
+	modelName := opts.Model
+	if modelName == "" {
+		modelName = geminiDefaultModel
+	}
+	model := client.GenerativeModel(modelName)
+	if opts.Temperature != nil {
+		model.SetTemperature(*opts.Temperature)
+	}
+	if opts.MaxOutputTokens > 0 {
+		model.SetMaxOutputTokens(int32(opts.MaxOutputTokens))
+	}
 
 	// Define the response schema
 	schema := &genai.Schema{
@@ -44,56 +69,90 @@ func (a *GeminiAssessor) Assess(docContent string, codeContents map[string]strin
 		Properties: map[string]*genai.Schema{
 			"is_in_sync": {Type: genai.TypeBoolean},
 			"reason":     {Type: genai.TypeString},
+			"confidence": {Type: genai.TypeNumber},
+			"findings": {
+				Type: genai.TypeArray,
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"doc_file":    {Type: genai.TypeString},
+						"line":        {Type: genai.TypeInteger},
+						"section":     {Type: genai.TypeString},
+						"code_file":   {Type: genai.TypeString},
+						"symbol":      {Type: genai.TypeString},
+						"category":    {Type: genai.TypeString, Format: "enum", Enum: Categories},
+						"description": {Type: genai.TypeString},
+						"suggestion":  {Type: genai.TypeString},
+					},
+					Required: []string{"category", "description"},
+				},
+			},
 		},
-		Required: []string{"is_in_sync", "reason"},
-	}
-
-	// Set the response mime type and schema
-	a.client.ResponseMIMEType = "application/json"
-	a.client.ResponseSchema = schema
-
-	codeStr := ""
-	for path, content := range codeContents {
-		codeStr += fmt.Sprintf("File: %s\n---\n%s\n---\n", path, content)
+		Required: []string{"is_in_sync", "reason", "confidence", "findings"},
 	}
 
-	prompt := fmt.Sprintf(`
-You are a senior software engineer reviewing documentation for a codebase.
-Your task is to determine if the documentation is in sync with the code.
+	// Set the response mime type and schema once, so that Assess doesn't
+	// modify the model and can be called concurrently.
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = schema
 
-Here is the documentation:
----
-%s
----
+	return &GeminiAssessor{client: model, timeout: opts.Timeout}, nil
+}
 
-And here is the code:
----
-%s
----
+// Assess uses the Gemini API to assess drift between code and documentation.
+func (a *GeminiAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	ctx, cancel := withTimeout(ctx, a.timeout)
+	defer cancel()
 
-Is the documentation in sync with the code?
-Please provide your answer in JSON format, with a boolean "is_in_sync" field and a "reason" field.
-`, docContent, codeStr)
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := a.client.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
-	if len(resp.Candidates) > 0 {
-		content := resp.Candidates[0].Content
-		if len(content.Parts) > 0 {
-			// The response should be a JSON string, unmarshal it
-			var result AssessmentResult
-			if err := json.Unmarshal([]byte(fmt.Sprintf("%s", content.Parts[0])), &result); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-			}
-			return &result, nil
-		}
+	text, err := geminiResponseText(resp)
+	if err != nil {
+		return nil, err
+	}
+	// The response should be a JSON string, unmarshal it
+	var result AssessmentResult
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &result, nil
+}
+
+// geminiKeyTransport sends the Gemini API key with every request.
+type geminiKeyTransport struct {
+	key  string
+	next http.RoundTripper
+}
+
+func (t *geminiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.key != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("x-goog-api-key", t.key)
 	}
+	return t.next.RoundTrip(req)
+}
 
-	return &AssessmentResult{
-		IsInSync: false,
-		Reason:   "Failed to parse response from Gemini API.",
-	}, nil
+// geminiResponseText returns the text of the first candidate of a response.
+// Blocked and empty responses are errors rather than drift, since the model
+// didn't assess anything.
+func geminiResponseText(resp *genai.GenerateContentResponse) (string, error) {
+	if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != genai.BlockReasonUnspecified {
+		return "", fmt.Errorf("gemini blocked the prompt: %s", resp.PromptFeedback.BlockReason)
+	}
+	if len(resp.Candidates) == 0 {
+		return "", fmt.Errorf("gemini returned no candidates")
+	}
+	candidate := resp.Candidates[0]
+	if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
+		return "", fmt.Errorf("gemini returned an empty candidate (finish reason: %s)", candidate.FinishReason)
+	}
+	return fmt.Sprintf("%s", candidate.Content.Parts[0]), nil
 }
@@ -0,0 +1,49 @@
+package assessor
+
+import (
+	"testing"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+func TestGeminiResponseText(t *testing.T) {
+	text, err := geminiResponseText(&genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: &genai.Content{Parts: []genai.Part{genai.Text(`{"is_in_sync": true}`)}}}},
+	})
+	if err != nil {
+		t.Fatalf("geminiResponseText() error = %v", err)
+	}
+	if text != `{"is_in_sync": true}` {
+		t.Errorf("geminiResponseText() = %q", text)
+	}
+
+	tests := []struct {
+		name    string
+		resp    *genai.GenerateContentResponse
+		wantErr string
+	}{
+		{
+			name:    "blocked prompt",
+			resp:    &genai.GenerateContentResponse{PromptFeedback: &genai.PromptFeedback{BlockReason: genai.BlockReasonSafety}},
+			wantErr: "gemini blocked the prompt: BlockReasonSafety",
+		},
+		{
+			name:    "no candidates",
+			resp:    &genai.GenerateContentResponse{},
+			wantErr: "gemini returned no candidates",
+		},
+		{
+			name:    "empty candidate",
+			resp:    &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonSafety}}},
+			wantErr: "gemini returned an empty candidate (finish reason: FinishReasonSafety)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := geminiResponseText(tt.resp)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("geminiResponseText() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
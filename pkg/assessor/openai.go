@@ -2,59 +2,152 @@ package assessor
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/driftee-ai/drift/pkg/config"
 	"github.com/sashabaranov/go-openai"
 )
 
+// ollamaDefaultBaseURL is the OpenAI-compatible endpoint of a local Ollama server.
+const ollamaDefaultBaseURL = "http://localhost:11434/v1"
+
 // OpenAIAssessor is a doc assessor that uses the OpenAI API.
+// It also talks to any server that implements the OpenAI chat completions
+// API, such as Ollama, vLLM or LM Studio.
 type OpenAIAssessor struct {
 	client *openai.Client
+	model  string
+	opts   config.ProviderOptions
 }
 
 // NewOpenAIAssessor creates a new OpenAIAssessor.
-func NewOpenAIAssessor() (*OpenAIAssessor, error) {
+func NewOpenAIAssessor(opts config.ProviderOptions) (*OpenAIAssessor, error) {
+	return newOpenAIAssessor(opts, nil)
+}
+
+func newOpenAIAssessor(opts config.ProviderOptions, h *providerHTTP) (*OpenAIAssessor, error) {
 	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
+	if apiKey == "" && h.needsKey() {
 		return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
 	}
-	client := openai.NewClient(apiKey)
-	return &OpenAIAssessor{client: client}, nil
+	clientConfig := openai.DefaultConfig(apiKey)
+	clientConfig.HTTPClient = openAIHTTPClient(h)
+
+	model := opts.Model
+	if model == "" {
+		model = openai.GPT3Dot5Turbo
+	}
+	return &OpenAIAssessor{client: openai.NewClientWithConfig(clientConfig), model: model, opts: opts}, nil
+}
+
+// NewOpenAICompatibleAssessor creates an OpenAIAssessor for the self-hosted
+// endpoint in opts.BaseURL. Self-hosted servers usually don't require
+// authentication, so the OPENAI_COMPATIBLE_API_KEY environment variable is
+// optional.
+func NewOpenAICompatibleAssessor(opts config.ProviderOptions) (*OpenAIAssessor, error) {
+	return newOpenAICompatibleAssessor(opts, nil)
+}
+
+func newOpenAICompatibleAssessor(opts config.ProviderOptions, h *providerHTTP) (*OpenAIAssessor, error) {
+	if opts.BaseURL == "" {
+		return nil, fmt.Errorf("provider_options.base_url must be set for an OpenAI-compatible provider")
+	}
+	if opts.Model == "" {
+		return nil, fmt.Errorf("provider_options.model must be set for an OpenAI-compatible provider")
+	}
+
+	clientConfig := openai.DefaultConfig(os.Getenv("OPENAI_COMPATIBLE_API_KEY"))
+	clientConfig.BaseURL = opts.BaseURL
+	clientConfig.HTTPClient = openAIHTTPClient(h)
+	client := openai.NewClientWithConfig(clientConfig)
+	return &OpenAIAssessor{client: client, model: opts.Model, opts: opts}, nil
+}
+
+// openAIHTTPClient returns the HTTP client of the go-openai client, which
+// captures the Retry-After header that the SDK's errors don't carry.
+func openAIHTTPClient(h *providerHTTP) *http.Client {
+	client := h.client()
+	if client == nil {
+		client = &http.Client{}
+	}
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client.Transport = retryAfterTransport{next: next}
+	return client
+}
+
+// retryAfterKey is the context key of the *time.Duration in which
+// retryAfterTransport stores the Retry-After header of a response.
+type retryAfterKey struct{}
+
+// retryAfterTransport stores the Retry-After header of each response in the
+// request's context, if it has a place for it.
+type retryAfterTransport struct {
+	next http.RoundTripper
+}
+
+func (t retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter, ok := req.Context().Value(retryAfterKey{}).(*time.Duration); ok {
+		*retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	return resp, nil
 }
 
 // Assess assesses the documentation against the code using the OpenAI API.
-func (a *OpenAIAssessor) Assess(docContent string, codeContents map[string]string) (*AssessmentResult, error) {
-	// Create the prompt
-	prompt := "The user wants to check if the documentation is in sync with the code. Please analyze the following documentation and code and determine if they are in sync. The output should be a JSON object with the following structure: {\"is_in_sync\": boolean, \"reason\": string}. The reason should be a short explanation of why the documentation is not in sync with the code. If they are in sync, the reason should be an empty string."
-	prompt += "\n\n--- Documentation ---\n" + docContent
-	for path, content := range codeContents {
-		prompt += "\n\n--- Code file: " + path + " ---\n" + content
+func (a *OpenAIAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the request
-	req := openai.ChatCompletionRequest{
-		Model: openai.GPT3Dot5Turbo,
+	chatReq := openai.ChatCompletionRequest{
+		Model: a.model,
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleUser,
 				Content: prompt,
 			},
 		},
+		MaxTokens: a.opts.MaxOutputTokens,
+		Seed:      a.opts.Seed,
 	}
+	if a.opts.Temperature != nil {
+		chatReq.Temperature = *a.opts.Temperature
+		if chatReq.Temperature == 0 {
+			// The temperature field is omitted when zero, which means the
+			// server default of 1; send the smallest float instead.
+			chatReq.Temperature = math.SmallestNonzeroFloat32
+		}
+	}
+
+	ctx, cancel := withTimeout(ctx, a.opts.Timeout)
+	defer cancel()
 
 	// Make the API call
-	resp, err := a.client.CreateChatCompletion(context.Background(), req)
+	var retryAfter time.Duration
+	resp, err := a.client.CreateChatCompletion(context.WithValue(ctx, retryAfterKey{}, &retryAfter), chatReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chat completion: %w", err)
+		err = fmt.Errorf("failed to create chat completion: %w", err)
+		if retryAfter > 0 {
+			return nil, &retryAfterError{err: err, retryAfter: retryAfter}
+		}
+		return nil, err
 	}
-
-	// Parse the response
-	var result AssessmentResult
-	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal assessment result: %w", err)
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("chat completion returned no choices")
 	}
 
-	return &result, nil
+	// Parse the response
+	return parseAssessmentResult(resp.Choices[0].Message.Content)
 }
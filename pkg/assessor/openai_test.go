@@ -0,0 +1,89 @@
+package assessor_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// chatCompletionRequest holds the request fields the tests assert on.
+type chatCompletionRequest struct {
+	Model       string   `json:"model"`
+	Temperature *float32 `json:"temperature"`
+	MaxTokens   int      `json:"max_tokens"`
+	Seed        *int     `json:"seed"`
+}
+
+// newChatCompletionServer starts a stand-in for an OpenAI-compatible chat
+// completions endpoint that answers with the given message content and
+// records the last request in got.
+func newChatCompletionServer(t *testing.T, got *chatCompletionRequest, content string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": content}},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAICompatibleAssessor_Assess(t *testing.T) {
+	var req chatCompletionRequest
+	server := newChatCompletionServer(t, &req, `{"is_in_sync": false, "reason": "age is undocumented"}`)
+
+	temperature := float32(0.2)
+	seed := 42
+	a, err := assessor.New("ollama", config.ProviderOptions{
+		BaseURL:         server.URL + "/v1",
+		Model:           "llama3",
+		Temperature:     &temperature,
+		MaxOutputTokens: 256,
+		Seed:            &seed,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := a.Assess(context.Background(), assessor.Request{
+		DocContent:   "# updateUser",
+		CodeContents: map[string]string{"code.go": "func updateUser(name string, age int) {}"},
+	})
+	if err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+	if got.IsInSync {
+		t.Errorf("IsInSync = true, want false")
+	}
+	if got.Reason != "age is undocumented" {
+		t.Errorf("Reason = %q, want %q", got.Reason, "age is undocumented")
+	}
+
+	if req.Model != "llama3" {
+		t.Errorf("model = %q, want %q", req.Model, "llama3")
+	}
+	if req.Temperature == nil || *req.Temperature != temperature {
+		t.Errorf("temperature = %v, want %v", req.Temperature, temperature)
+	}
+	if req.MaxTokens != 256 {
+		t.Errorf("max_tokens = %d, want 256", req.MaxTokens)
+	}
+	if req.Seed == nil || *req.Seed != seed {
+		t.Errorf("seed = %v, want %d", req.Seed, seed)
+	}
+}
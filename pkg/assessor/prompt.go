@@ -0,0 +1,133 @@
+package assessor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// DefaultPromptTemplate is the built-in prompt template, used unless a
+// custom template is configured.
+const DefaultPromptTemplate = `You are a senior software engineer reviewing documentation for a codebase.
+Your task is to determine if the documentation is in sync with the code.
+
+Here is the documentation:
+
+<documentation>
+{{section .Docs}}</documentation>
+
+And here is the code:
+{{range .CodeFiles}}
+<code path="{{.Path}}">
+{{section .Content}}</code>
+{{end}}
+Is the documentation in sync with the code?
+Respond with a single JSON object and nothing else, with a boolean "is_in_sync" field, a "reason" field, a "confidence" field and a "findings" field.
+"reason" is a short explanation of why the documentation is not in sync with the code, or an empty string if it is.
+"confidence" is a number from 0 to 1 saying how sure you are of "is_in_sync". Use a low confidence when the differences may be cosmetic, such as wording or formatting.
+{{.FindingsFormat}}
+`
+
+// PromptData is the data available to prompt templates.
+type PromptData struct {
+	// Rule is the rule being checked, e.g. {{.Rule.Name}}.
+	Rule config.Rule
+	// Docs is the content of the rule's doc files.
+	Docs string
+	// CodeFiles are the rule's code files, sorted by path.
+	CodeFiles []CodeFile
+	// FindingsFormat describes the format of the "findings" field.
+	FindingsFormat string
+}
+
+// CodeFile is a code file in PromptData.
+type CodeFile struct {
+	Path    string
+	Content string
+}
+
+// promptFuncs are the functions available to prompt templates, in addition
+// to the text/template builtins.
+var promptFuncs = template.FuncMap{
+	// section ends content with exactly one newline, so that a delimiter
+	// placed after it starts on its own line.
+	"section": func(content string) string {
+		if content == "" || strings.HasSuffix(content, "\n") {
+			return content
+		}
+		return content + "\n"
+	},
+}
+
+// Prompt renders the prompts sent to providers from a text/template, followed
+// by optional extra instructions.
+type Prompt struct {
+	tmpl         *template.Template
+	instructions string
+}
+
+var defaultPrompt = template.Must(template.New("prompt").Funcs(promptFuncs).Parse(DefaultPromptTemplate))
+
+// DefaultPrompt returns the built-in prompt without extra instructions.
+func DefaultPrompt() *Prompt {
+	return &Prompt{tmpl: defaultPrompt}
+}
+
+// samplePromptData is rendered by NewPrompt to catch errors that parsing
+// alone doesn't, such as misspelt fields.
+var samplePromptData = PromptData{
+	Rule:           config.Rule{Name: "Example", Code: []string{"src/*.go"}, Docs: []string{"docs/*.md"}},
+	Docs:           "# Example\n",
+	CodeFiles:      []CodeFile{{Path: "src/example.go", Content: "package example\n"}},
+	FindingsFormat: findingsInstructions,
+}
+
+// NewPrompt creates a Prompt from the given settings. An empty template
+// selects DefaultPromptTemplate. Custom templates are rendered once with
+// sample data, so that mistakes are reported before any rule is checked.
+func NewPrompt(opts config.PromptOptions) (*Prompt, error) {
+	p := DefaultPrompt()
+	if opts.Template != "" {
+		tmpl, err := template.New("prompt").Funcs(promptFuncs).Option("missingkey=error").Parse(opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt template: %w", err)
+		}
+		if err := tmpl.Execute(io.Discard, samplePromptData); err != nil {
+			return nil, fmt.Errorf("invalid prompt template: %w", err)
+		}
+		p.tmpl = tmpl
+	}
+	p.instructions = strings.TrimSpace(opts.Instructions)
+	return p, nil
+}
+
+// Render returns the prompt for assessing the given rule, documentation and
+// code. The output depends only on its inputs: code files are sorted by path,
+// so that identical inputs always give identical prompts.
+func (p *Prompt) Render(rule config.Rule, docContent string, codeContents map[string]string) (string, error) {
+	data := PromptData{
+		Rule:           rule,
+		Docs:           docContent,
+		CodeFiles:      make([]CodeFile, 0, len(codeContents)),
+		FindingsFormat: findingsInstructions,
+	}
+	for path, content := range codeContents {
+		data.CodeFiles = append(data.CodeFiles, CodeFile{Path: path, Content: content})
+	}
+	sort.Slice(data.CodeFiles, func(i, j int) bool { return data.CodeFiles[i].Path < data.CodeFiles[j].Path })
+
+	var b strings.Builder
+	if err := p.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+	if p.instructions != "" {
+		b.WriteString("\nAdditional instructions:\n")
+		b.WriteString(p.instructions)
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,189 @@
+package assessor_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/prompts")
+
+// repoTestdata is the shared testdata directory at the root of the module.
+const repoTestdata = "../../testdata"
+
+func readTestdata(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(repoTestdata, path))
+	if err != nil {
+		t.Fatalf("failed to read test input: %v", err)
+	}
+	return string(data)
+}
+
+func TestDefaultPrompt_Golden(t *testing.T) {
+	cases, err := filepath.Glob(filepath.Join(repoTestdata, "e2e", "*", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := map[string]struct {
+		docs string
+		code map[string]string
+	}{}
+	for _, dir := range cases {
+		rel, _ := filepath.Rel(repoTestdata, dir)
+		codePath := filepath.ToSlash(filepath.Join("testdata", rel, "code.go"))
+		inputs[filepath.Base(dir)] = struct {
+			docs string
+			code map[string]string
+		}{
+			docs: readTestdata(t, filepath.Join(rel, "docs.md")),
+			code: map[string]string{codePath: readTestdata(t, filepath.Join(rel, "code.go"))},
+		}
+	}
+	inputs["multiple_files"] = struct {
+		docs string
+		code map[string]string
+	}{
+		docs: readTestdata(t, "docs/api/users.md"),
+		code: map[string]string{
+			"testdata/src/api/user.go":                                  readTestdata(t, "src/api/user.go"),
+			"testdata/e2e/true_negatives/in_sync_example/code.go":       readTestdata(t, "e2e/true_negatives/in_sync_example/code.go"),
+			"testdata/e2e/true_positives/missing_param_in_docs/code.go": readTestdata(t, "e2e/true_positives/missing_param_in_docs/code.go"),
+		},
+	}
+
+	for name, in := range inputs {
+		t.Run(name, func(t *testing.T) {
+			got, err := assessor.DefaultPrompt().Render(config.Rule{Name: name}, in.docs, in.code)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			golden := filepath.Join("testdata", "prompts", name+".golden")
+
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run go test with -update to create it): %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("prompt differs from %s (run go test with -update to accept the change)\ngot:\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestDefaultPrompt_Deterministic(t *testing.T) {
+	code := map[string]string{}
+	for _, name := range []string{"e.go", "b.go", "a.go", "d.go", "c.go"} {
+		code[name] = "package " + strings.TrimSuffix(name, ".go")
+	}
+
+	render := func() string {
+		prompt, err := assessor.DefaultPrompt().Render(config.Rule{}, "# Docs", code)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return prompt
+	}
+	first := render()
+	for i := 0; i < 20; i++ {
+		if got := render(); got != first {
+			t.Fatalf("Render() is not deterministic:\n%s\n---\n%s", first, got)
+		}
+	}
+	if strings.Index(first, `path="a.go"`) > strings.Index(first, `path="b.go"`) {
+		t.Errorf("code files are not sorted by path:\n%s", first)
+	}
+}
+
+func TestNewPrompt(t *testing.T) {
+	rule := config.Rule{Name: "Users", Docs: []string{"docs/users.md"}}
+	code := map[string]string{"b.go": "package b", "a.go": "package a"}
+
+	tests := []struct {
+		name string
+		opts config.PromptOptions
+		want string
+	}{
+		{
+			name: "custom template",
+			opts: config.PromptOptions{
+				Template: `Rule {{.Rule.Name}} ({{index .Rule.Docs 0}}): {{.Docs}}{{range .CodeFiles}} [{{.Path}}: {{.Content}}]{{end}}`,
+			},
+			want: "Rule Users (docs/users.md): # Users [a.go: package a] [b.go: package b]",
+		},
+		{
+			name: "instructions are appended",
+			opts: config.PromptOptions{
+				Template:     "Check {{.Rule.Name}}.",
+				Instructions: "  Examples are illustrative, don't flag them.\n",
+			},
+			want: "Check Users.\nAdditional instructions:\nExamples are illustrative, don't flag them.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := assessor.NewPrompt(tt.opts)
+			if err != nil {
+				t.Fatalf("NewPrompt() error = %v", err)
+			}
+			got, err := p.Render(rule, "# Users", code)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPrompt_DefaultWithInstructions(t *testing.T) {
+	p, err := assessor.NewPrompt(config.PromptOptions{Instructions: "Ignore typos."})
+	if err != nil {
+		t.Fatalf("NewPrompt() error = %v", err)
+	}
+	got, err := p.Render(config.Rule{}, "# Docs", nil)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want, err := assessor.DefaultPrompt().Render(config.Rule{}, "# Docs", nil)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got != want+"\nAdditional instructions:\nIgnore typos.\n" {
+		t.Errorf("Render() = %q, want the default prompt followed by the instructions", got)
+	}
+}
+
+func TestNewPrompt_InvalidTemplate(t *testing.T) {
+	if _, err := assessor.NewPrompt(config.PromptOptions{Template: "{{.Docs"}); err == nil {
+		t.Errorf("expected an error for an unterminated action")
+	}
+}
+
+func TestNewPrompt_UnknownField(t *testing.T) {
+	for _, tmpl := range []string{
+		"{{.Rule.Owner}}",
+		"{{range .CodeFiles}}{{.Contents}}{{end}}",
+		"{{.Docs | upper}}",
+	} {
+		if _, err := assessor.NewPrompt(config.PromptOptions{Template: tmpl}); err == nil {
+			t.Errorf("NewPrompt(%q) expected an error", tmpl)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package assessor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// Fixture is a recorded provider response, stored as <PromptHash>.json in a
+// fixtures directory.
+type Fixture struct {
+	// Provider is the provider that answered. Replay hands the response to
+	// this provider, so that it is parsed the way the live answer was.
+	Provider string `json:"provider"`
+	// Model is the model that answered, for reference.
+	Model string `json:"model,omitempty"`
+	// PromptHash identifies the prompt; see PromptHash.
+	PromptHash string `json:"prompt_hash"`
+	// Prompt is the prompt that was sent, for reviewing fixtures.
+	Prompt string `json:"prompt"`
+	// Response is the raw body of the provider's HTTP response.
+	Response string `json:"response"`
+}
+
+// PromptHash returns the key under which the response to prompt is recorded.
+func PromptHash(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+func fixturePath(dir, hash string) string {
+	return filepath.Join(dir, hash+".json")
+}
+
+// exchange carries the response body of a provider's API call between an
+// assessment and the transport that sends its request.
+type exchange struct {
+	mu   sync.Mutex
+	body []byte
+}
+
+type exchangeKey struct{}
+
+// exchangeFrom returns the exchange of the assessment that sent req, if any.
+func exchangeFrom(req *http.Request) *exchange {
+	x, _ := req.Context().Value(exchangeKey{}).(*exchange)
+	return x
+}
+
+// recordingTransport keeps the body of every successful response in the
+// request's exchange. After retries, the exchange holds the body of the
+// response that was parsed.
+type recordingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	x := exchangeFrom(req)
+	if err != nil || x == nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	x.mu.Lock()
+	x.body = body
+	x.mu.Unlock()
+	return resp, nil
+}
+
+// replayTransport answers every request with the body in its exchange,
+// without using the network.
+type replayTransport struct{}
+
+func (replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	x := exchangeFrom(req)
+	if x == nil {
+		return nil, fmt.Errorf("no recorded response for %s", req.URL)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(x.body)),
+		ContentLength: int64(len(x.body)),
+		Request:       req,
+	}, nil
+}
+
+// RecordingAssessor records the raw API responses of a provider as fixtures
+// for the replay provider.
+type RecordingAssessor struct {
+	inner    DocAssessor
+	dir      string
+	provider string
+	model    string
+}
+
+// NewRecordingAssessor creates the assessor for provider and opts, recording
+// its API responses in dir. Only providers that call an HTTP API can be
+// recorded.
+func NewRecordingAssessor(provider string, opts config.ProviderOptions, dir string) (*RecordingAssessor, error) {
+	inner, err := newAPIAssessor(provider, opts, &providerHTTP{transport: &recordingTransport{next: http.DefaultTransport}})
+	if err != nil {
+		return nil, err
+	}
+	return &RecordingAssessor{inner: inner, dir: dir, provider: provider, model: opts.Model}, nil
+}
+
+// Assess assesses the request with the provider and records the response it
+// parsed. Failed assessments are not recorded.
+func (a *RecordingAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	x := &exchange{}
+	result, err := a.inner.Assess(context.WithValue(ctx, exchangeKey{}, x), req)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return nil, err
+	}
+	x.mu.Lock()
+	response := x.body
+	x.mu.Unlock()
+	if response == nil {
+		return nil, fmt.Errorf("failed to record response: %s sent no request", a.provider)
+	}
+
+	// Fixtures are meant to be reviewed, so keep the prompt's markup readable.
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	hash := PromptHash(prompt)
+	if err := enc.Encode(Fixture{
+		Provider:   a.provider,
+		Model:      a.model,
+		PromptHash: hash,
+		Prompt:     prompt,
+		Response:   string(response),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(a.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	if err := os.WriteFile(fixturePath(a.dir, hash), data.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to record response: %w", err)
+	}
+	return result, nil
+}
+
+// ReplayAssessor answers with the responses recorded by a RecordingAssessor,
+// looked up by the hash of the prompt. Each response is handed to the
+// provider that gave it, through a transport that never uses the network.
+type ReplayAssessor struct {
+	dir string
+
+	mu        sync.Mutex
+	providers map[string]DocAssessor
+}
+
+// NewReplayAssessor creates a ReplayAssessor serving the fixtures in
+// opts.Fixtures.
+func NewReplayAssessor(opts config.ProviderOptions) (*ReplayAssessor, error) {
+	if opts.Fixtures == "" {
+		return nil, fmt.Errorf("provider_options.fixtures must be set for the replay provider")
+	}
+	return &ReplayAssessor{dir: opts.Fixtures, providers: make(map[string]DocAssessor)}, nil
+}
+
+// Assess returns the recorded response to the request's prompt, as parsed by
+// the provider that recorded it.
+func (a *ReplayAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := PromptHash(prompt)
+	data, err := os.ReadFile(fixturePath(a.dir, hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no recorded response for prompt %s in %s; record one with drift check --record", hash, a.dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", hash, err)
+	}
+	provider, err := a.provider(fixture)
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", hash, err)
+	}
+	return provider.Assess(context.WithValue(ctx, exchangeKey{}, &exchange{body: []byte(fixture.Response)}), req)
+}
+
+// provider returns the assessor that parses the responses of the fixture's
+// provider and model, creating it on first use.
+func (a *ReplayAssessor) provider(fixture Fixture) (DocAssessor, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := fixture.Provider + ":" + fixture.Model
+	if p, ok := a.providers[key]; ok {
+		return p, nil
+	}
+	// The transport ignores the endpoint, but OpenAI-compatible providers
+	// require one.
+	opts := config.ProviderOptions{Model: fixture.Model, BaseURL: "http://replay.invalid"}
+	p, err := newAPIAssessor(fixture.Provider, opts, &providerHTTP{transport: replayTransport{}, offline: true})
+	if err != nil {
+		return nil, err
+	}
+	a.providers[key] = p
+	return p, nil
+}
@@ -0,0 +1,217 @@
+package assessor_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	body := anthropicText(`{"is_in_sync": false, "reason": "age is undocumented", "findings": [
+		{"symbol": "updateUser", "category": "missing_param", "description": "age"}]}`)
+	server := newAnthropicServer(t, http.StatusOK, body)
+	setupAnthropicEnv(t, server.URL)
+	req := assessor.Request{
+		Rule:         config.Rule{Name: "Users"},
+		DocContent:   "# GetUser",
+		CodeContents: map[string]string{"user.go": "func GetUser(id int) {}"},
+	}
+
+	recorder, err := assessor.NewRecordingAssessor("anthropic", config.ProviderOptions{Model: "claude-test"}, dir)
+	if err != nil {
+		t.Fatalf("NewRecordingAssessor() error = %v", err)
+	}
+	want, err := recorder.Assess(context.Background(), req)
+	if err != nil {
+		t.Fatalf("recording Assess() error = %v", err)
+	}
+
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		t.Fatalf("RenderPrompt() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, assessor.PromptHash(prompt)+".json"))
+	if err != nil {
+		t.Fatalf("fixture not recorded: %v", err)
+	}
+	var fixture assessor.Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("fixture is not valid JSON: %v", err)
+	}
+	if fixture.Provider != "anthropic" || fixture.Model != "claude-test" || fixture.Prompt != prompt {
+		t.Errorf("fixture = %+v, want the provider, model and prompt", fixture)
+	}
+	if fixture.Response != body {
+		t.Errorf("fixture response = %q, want the raw response body %q", fixture.Response, body)
+	}
+
+	// Replay needs neither the network nor an API key.
+	server.Close()
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	replay, err := assessor.NewReplayAssessor(config.ProviderOptions{Fixtures: dir})
+	if err != nil {
+		t.Fatalf("NewReplayAssessor() error = %v", err)
+	}
+	got, err := replay.Assess(context.Background(), req)
+	if err != nil {
+		t.Fatalf("replaying Assess() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed result = %+v, want %+v", got, want)
+	}
+
+	// A different prompt has no recording.
+	req.DocContent = "# GetUser v2"
+	if _, err := replay.Assess(context.Background(), req); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("Assess() error = %v, want a missing fixture error", err)
+	}
+}
+
+func TestRecordingAssessor_FailedAssessment(t *testing.T) {
+	dir := t.TempDir()
+	server := newAnthropicServer(t, http.StatusOK, anthropicText("I think it is fine."))
+	setupAnthropicEnv(t, server.URL)
+
+	recorder, err := assessor.NewRecordingAssessor("anthropic", config.ProviderOptions{Model: "claude-test"}, dir)
+	if err != nil {
+		t.Fatalf("NewRecordingAssessor() error = %v", err)
+	}
+	if _, err := recorder.Assess(context.Background(), assessor.Request{
+		DocContent:   "# GetUser",
+		CodeContents: map[string]string{"user.go": "func GetUser(id int) {}"},
+	}); err == nil {
+		t.Fatalf("Assess() expected an error for a malformed answer")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("failed assessment was recorded: %v", entries)
+	}
+}
+
+func TestNewRecordingAssessor_NoAPI(t *testing.T) {
+	for _, provider := range []string{"dummy", "replay", "consensus"} {
+		if _, err := assessor.NewRecordingAssessor(provider, config.ProviderOptions{}, t.TempDir()); err == nil {
+			t.Errorf("NewRecordingAssessor(%q) expected an error", provider)
+		}
+	}
+}
+
+// writeFixture records response as the answer of provider to the prompt of
+// req in dir.
+func writeFixture(t *testing.T, dir, provider, model string, req assessor.Request, response string) {
+	t.Helper()
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		t.Fatalf("RenderPrompt() error = %v", err)
+	}
+	hash := assessor.PromptHash(prompt)
+	data, err := json.Marshal(assessor.Fixture{Provider: provider, Model: model, PromptHash: hash, Prompt: prompt, Response: response})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, hash+".json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplayAssessor_Providers(t *testing.T) {
+	answer := `{"is_in_sync": false, "reason": "age is undocumented", "confidence": 0.9, "findings": []}`
+	quoted, _ := json.Marshal(answer)
+	tests := []struct {
+		provider string
+		model    string
+		response string
+		wantErr  string
+	}{
+		{
+			provider: "gemini",
+			response: `{"candidates": [{"content": {"parts": [{"text": ` + string(quoted) + `}], "role": "model"}, "finishReason": "STOP"}]}`,
+		},
+		{
+			provider: "gemini",
+			response: `{"promptFeedback": {"blockReason": "SAFETY"}}`,
+			wantErr:  "blocked: prompt",
+		},
+		{
+			provider: "openai",
+			response: `{"id": "chatcmpl-1", "object": "chat.completion", "choices": [{"index": 0, "message": {"role": "assistant", "content": ` + string(quoted) + `}, "finish_reason": "stop"}]}`,
+		},
+		{
+			provider: "ollama",
+			model:    "llama3",
+			response: `{"choices": []}`,
+			wantErr:  "no choices",
+		},
+		{
+			provider: "anthropic",
+			response: anthropicText("```json\n" + answer + "\n```"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+" "+tt.wantErr, func(t *testing.T) {
+			for _, env := range []string{"GEMINI_API_KEY", "OPENAI_API_KEY", "ANTHROPIC_API_KEY"} {
+				t.Setenv(env, "")
+			}
+			dir := t.TempDir()
+			req := assessor.Request{DocContent: "# updateUser", CodeContents: map[string]string{"code.go": "func updateUser(age int) {}"}}
+			writeFixture(t, dir, tt.provider, tt.model, req, tt.response)
+
+			replay, err := assessor.NewReplayAssessor(config.ProviderOptions{Fixtures: dir})
+			if err != nil {
+				t.Fatalf("NewReplayAssessor() error = %v", err)
+			}
+			got, err := replay.Assess(context.Background(), req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Assess() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Assess() error = %v", err)
+			}
+			if got.IsInSync || got.Reason != "age is undocumented" || got.Confidence == nil || *got.Confidence != 0.9 {
+				t.Errorf("Assess() = %+v, want the recorded answer", got)
+			}
+		})
+	}
+}
+
+func TestReplayAssessor_SharedFixtures(t *testing.T) {
+	// testdata/fixtures holds hand-edited recordings used by the
+	// integration tests, including an answer wrapped in a code fence.
+	replay, err := assessor.NewReplayAssessor(config.ProviderOptions{Fixtures: "../../testdata/fixtures"})
+	if err != nil {
+		t.Fatalf("NewReplayAssessor() error = %v", err)
+	}
+
+	dir := "testdata/e2e/true_positives/missing_param_in_docs/"
+	docs, err := os.ReadFile("../../" + dir + "docs.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := os.ReadFile("../../" + dir + "code.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The docs are concatenated the way files.ReadAndConcatenate does.
+	got, err := replay.Assess(context.Background(), assessor.Request{
+		DocContent:   string(docs) + "\n--- End of file: " + dir + "docs.md ---\n",
+		CodeContents: map[string]string{dir + "code.go": string(code)},
+	})
+	if err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+	if got.IsInSync || len(got.Findings) != 1 || got.Findings[0].Category != assessor.CategoryMissingParam {
+		t.Errorf("Assess() = %+v, want one missing_param finding", got)
+	}
+}
@@ -0,0 +1,259 @@
+package assessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+)
+
+// Default retry settings, used for fields left unset in config.RetryOptions.
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// APIError is an error response from a provider's HTTP API.
+type APIError struct {
+	StatusCode int
+	// RetryAfter is the delay requested by the Retry-After header, or 0.
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (status %d)", e.Message, e.StatusCode)
+}
+
+// retryAfterError adds the delay requested by the Retry-After header to an
+// error of an SDK that doesn't expose it.
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date. It returns 0 if the header is empty or invalid.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// isRetryableStatus reports whether an HTTP status indicates a transient
+// failure: rate limiting, a server error or an overloaded service.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout, 529:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether err is worth retrying and how long the provider
+// asked us to wait, if at all. It understands the error types of every
+// provider SDK in use.
+func retryable(err error) (bool, time.Duration) {
+	var delayed *retryAfterError
+	if errors.As(err, &delayed) {
+		ok, _ := retryable(delayed.err)
+		return ok, delayed.retryAfter
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return isRetryableStatus(apiErr.StatusCode), apiErr.RetryAfter
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return isRetryableStatus(googleErr.Code), parseRetryAfter(googleErr.Header.Get("Retry-After"))
+	}
+	var openaiErr *openai.APIError
+	if errors.As(err, &openaiErr) {
+		return isRetryableStatus(openaiErr.HTTPStatusCode), 0
+	}
+	var openaiReqErr *openai.RequestError
+	if errors.As(err, &openaiReqErr) {
+		return isRetryableStatus(openaiReqErr.HTTPStatusCode), 0
+	}
+	// A per-request timeout from provider_options.timeout; the caller checks
+	// separately whether its own context is done.
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true, 0
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true, 0
+	}
+	return false, 0
+}
+
+// RetryingAssessor retries transient failures of another DocAssessor with
+// exponential backoff and jitter, honoring Retry-After when the provider
+// sends it.
+type RetryingAssessor struct {
+	inner          DocAssessor
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	// sleep waits for d or until ctx is done; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingAssessor wraps inner so that failed assessments are retried
+// according to opts.
+func NewRetryingAssessor(inner DocAssessor, opts config.RetryOptions) *RetryingAssessor {
+	a := &RetryingAssessor{
+		inner:          inner,
+		maxAttempts:    opts.MaxAttempts,
+		initialBackoff: opts.InitialBackoff,
+		maxBackoff:     opts.MaxBackoff,
+		sleep:          sleepContext,
+	}
+	if a.maxAttempts <= 0 {
+		a.maxAttempts = defaultMaxAttempts
+	}
+	if a.initialBackoff <= 0 {
+		a.initialBackoff = defaultInitialBackoff
+	}
+	if a.maxBackoff <= 0 {
+		a.maxBackoff = defaultMaxBackoff
+	}
+	return a
+}
+
+// Assess calls the wrapped assessor until it succeeds, fails with an error
+// that isn't transient, or runs out of attempts.
+func (a *RetryingAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := a.inner.Assess(ctx, req)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		ok, retryAfter := retryable(err)
+		if !ok {
+			return nil, err
+		}
+		if attempt >= a.maxAttempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		delay := retryAfter
+		if delay <= 0 {
+			delay = a.backoff(attempt)
+		}
+		if err := a.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns the delay before the retry following the given attempt:
+// the initial backoff doubled for each previous attempt, capped at the
+// maximum, with the upper half randomised to spread out concurrent retries.
+func (a *RetryingAssessor) backoff(attempt int) time.Duration {
+	delay := a.initialBackoff
+	for i := 1; i < attempt && delay < a.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > a.maxBackoff {
+		delay = a.maxBackoff
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimiter spaces out requests to stay under a requests-per-minute limit.
+// It is safe for concurrent use, so one limiter can be shared by every rule
+// check.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerMinute requests per
+// minute.
+func NewRateLimiter(requestsPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// Wait blocks until the caller may send a request or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return l.sleep(ctx, wait)
+}
+
+// RateLimitedAssessor waits for a RateLimiter before every assessment.
+type RateLimitedAssessor struct {
+	inner   DocAssessor
+	limiter *RateLimiter
+}
+
+// NewRateLimitedAssessor wraps inner so that it waits for limiter before
+// each request.
+func NewRateLimitedAssessor(inner DocAssessor, limiter *RateLimiter) *RateLimitedAssessor {
+	return &RateLimitedAssessor{inner: inner, limiter: limiter}
+}
+
+// Assess waits for the rate limiter and then calls the wrapped assessor.
+func (a *RateLimitedAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	if err := a.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return a.inner.Assess(ctx, req)
+}
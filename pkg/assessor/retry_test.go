@@ -0,0 +1,242 @@
+package assessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+)
+
+// flakyAssessor fails with err for the first failures calls and succeeds
+// afterwards.
+type flakyAssessor struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (a *flakyAssessor) Assess(ctx context.Context, req Request) (*AssessmentResult, error) {
+	a.calls++
+	if a.calls <= a.failures {
+		return nil, a.err
+	}
+	return &AssessmentResult{IsInSync: true}, nil
+}
+
+// newTestRetryingAssessor returns a RetryingAssessor that records its delays
+// instead of sleeping.
+func newTestRetryingAssessor(inner DocAssessor, opts config.RetryOptions) (*RetryingAssessor, *[]time.Duration) {
+	var delays []time.Duration
+	a := NewRetryingAssessor(inner, opts)
+	a.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return a, &delays
+}
+
+func TestRetryingAssessor(t *testing.T) {
+	rateLimited := &APIError{StatusCode: http.StatusTooManyRequests, Message: "rate limited"}
+
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "succeeds first time", failures: 0, err: rateLimited, wantCalls: 1},
+		{name: "recovers after retries", failures: 2, err: rateLimited, wantCalls: 3},
+		{name: "gives up after max attempts", failures: 5, err: rateLimited, wantErr: true, wantCalls: 3},
+		{name: "retries server errors", failures: 1, err: &APIError{StatusCode: 529, Message: "overloaded"}, wantCalls: 2},
+		{name: "retries request timeouts", failures: 1, err: fmt.Errorf("request failed: %w", context.DeadlineExceeded), wantCalls: 2},
+		{name: "retries openai rate limits", failures: 1, err: &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests}, wantCalls: 2},
+		{name: "retries gemini unavailability", failures: 1, err: fmt.Errorf("failed to generate content: %w", &googleapi.Error{Code: http.StatusServiceUnavailable}), wantCalls: 2},
+		{name: "does not retry client errors", failures: 1, err: &APIError{StatusCode: http.StatusUnauthorized, Message: "bad key"}, wantErr: true, wantCalls: 1},
+		{name: "does not retry other errors", failures: 1, err: errors.New("malformed response"), wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &flakyAssessor{failures: tt.failures, err: tt.err}
+			a, delays := newTestRetryingAssessor(inner, config.RetryOptions{MaxAttempts: 3})
+
+			result, err := a.Assess(context.Background(), Request{DocContent: "docs"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Assess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !result.IsInSync {
+				t.Errorf("IsInSync = false, want true")
+			}
+			if err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Assess() error = %v, want it to wrap %v", err, tt.err)
+			}
+			if inner.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", inner.calls, tt.wantCalls)
+			}
+			if len(*delays) != tt.wantCalls-1 {
+				t.Errorf("slept %d times, want %d", len(*delays), tt.wantCalls-1)
+			}
+		})
+	}
+}
+
+func TestRetryingAssessor_Backoff(t *testing.T) {
+	inner := &flakyAssessor{failures: 4, err: &APIError{StatusCode: http.StatusBadGateway}}
+	a, delays := newTestRetryingAssessor(inner, config.RetryOptions{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	})
+
+	if _, err := a.Assess(context.Background(), Request{DocContent: "docs"}); err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+
+	// The delay doubles from 1s up to the 3s cap, with up to half of it
+	// replaced by jitter.
+	wantMax := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}
+	if len(*delays) != len(wantMax) {
+		t.Fatalf("delays = %v, want %d of them", *delays, len(wantMax))
+	}
+	for i, d := range *delays {
+		if d < wantMax[i]/2 || d > wantMax[i] {
+			t.Errorf("delay %d = %v, want between %v and %v", i, d, wantMax[i]/2, wantMax[i])
+		}
+	}
+}
+
+func TestRetryingAssessor_RetryAfter(t *testing.T) {
+	inner := &flakyAssessor{failures: 1, err: &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 7 * time.Second}}
+	a, delays := newTestRetryingAssessor(inner, config.RetryOptions{})
+
+	if _, err := a.Assess(context.Background(), Request{DocContent: "docs"}); err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+		t.Errorf("delays = %v, want [7s]", *delays)
+	}
+}
+
+func TestRetryingAssessor_OpenAIRetryAfter(t *testing.T) {
+	// The go-openai client's errors don't carry the Retry-After header, so
+	// the assessor captures it.
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": {"message": "slow down"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"is_in_sync\": true}"}}]}`))
+	}))
+	defer server.Close()
+
+	inner, err := newOpenAICompatibleAssessor(config.ProviderOptions{BaseURL: server.URL + "/v1", Model: "llama3"}, nil)
+	if err != nil {
+		t.Fatalf("newOpenAICompatibleAssessor() error = %v", err)
+	}
+	a, delays := newTestRetryingAssessor(inner, config.RetryOptions{})
+
+	if _, err := a.Assess(context.Background(), Request{DocContent: "docs"}); err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != 7*time.Second {
+		t.Errorf("delays = %v, want [7s]", *delays)
+	}
+}
+
+func TestRetryingAssessor_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	inner := &flakyAssessor{failures: 5, err: fmt.Errorf("request failed: %w", context.Canceled)}
+	a, _ := newTestRetryingAssessor(inner, config.RetryOptions{})
+
+	if _, err := a.Assess(ctx, Request{DocContent: "docs"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Assess() error = %v, want context.Canceled", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("calls = %d, want 1", inner.calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("12"); got != 12*time.Second {
+		t.Errorf("parseRetryAfter(12) = %v, want 12s", got)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %v, want up to 1m", date, got)
+	}
+	for _, value := range []string{"", "soon", "-3"} {
+		if got := parseRetryAfter(value); got != 0 {
+			t.Errorf("parseRetryAfter(%q) = %v, want 0", value, got)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	var waits []time.Duration
+
+	l := NewRateLimiter(30)
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		waits = append(waits, d)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	// 30 requests per minute leaves 2s between requests; the first one
+	// goes out immediately.
+	want := []time.Duration{2 * time.Second, 4 * time.Second}
+	if fmt.Sprint(waits) != fmt.Sprint(want) {
+		t.Errorf("waits = %v, want %v", waits, want)
+	}
+
+	// Once the reserved slots have passed, requests go out immediately again.
+	now = now.Add(time.Minute)
+	waits = nil
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if len(waits) != 0 {
+		t.Errorf("waits = %v, want none", waits)
+	}
+}
+
+func TestRateLimitedAssessor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	inner := &flakyAssessor{}
+	l := NewRateLimiter(60)
+	l.next = time.Now().Add(time.Hour)
+	a := NewRateLimitedAssessor(inner, l)
+
+	if _, err := a.Assess(ctx, Request{DocContent: "docs"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Assess() error = %v, want context.Canceled", err)
+	}
+	if inner.calls != 0 {
+		t.Errorf("calls = %d, want 0", inner.calls)
+	}
+}
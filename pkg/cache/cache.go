@@ -0,0 +1,211 @@
+// Package cache stores assessment results on disk so that rules whose code
+// and documentation haven't changed are not sent to the provider again.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+// DefaultDir is the cache directory used unless another one is given,
+// relative to the working directory.
+const DefaultDir = ".drift/cache"
+
+// Store is a directory of cached assessment results, one JSON file per key.
+type Store struct {
+	dir string
+}
+
+// entry is the content of a cache file.
+type entry struct {
+	CreatedAt time.Time                  `json:"created_at"`
+	Result    *assessor.AssessmentResult `json:"result"`
+}
+
+// Stats summarises the content of a Store.
+type Stats struct {
+	Entries int
+	Bytes   int64
+}
+
+// NewStore returns a Store backed by dir. The directory is created on the
+// first write.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory backing the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Get returns the result cached under key, if any. Unreadable entries are
+// treated as missing.
+func (s *Store) Get(key string) (*assessor.AssessmentResult, bool) {
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Result == nil {
+		return nil, false
+	}
+	return e.Result, true
+}
+
+// Put caches result under key. The entry is written to a temporary file and
+// renamed into place, so concurrent readers never see a partial entry.
+func (s *Store) Put(key string, result *assessor.AssessmentResult) error {
+	data, err := json.Marshal(entry{CreatedAt: time.Now().UTC(), Result: result})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+// Clear removes every cached entry and returns how many there were.
+func (s *Store) Clear() (int, error) {
+	paths, err := s.entries()
+	if err != nil {
+		return 0, err
+	}
+	for i, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return i, err
+		}
+	}
+	return len(paths), nil
+}
+
+// Stats returns the number and total size of the cached entries.
+func (s *Store) Stats() (Stats, error) {
+	var stats Stats
+	paths, err := s.entries()
+	if err != nil {
+		return stats, err
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return stats, err
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+	}
+	return stats, nil
+}
+
+// entries returns the paths of all cache files. A missing directory is an
+// empty cache.
+func (s *Store) entries() ([]string, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, e := range dirEntries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".json" {
+			paths = append(paths, filepath.Join(s.dir, e.Name()))
+		}
+	}
+	return paths, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// Key derives the cache key of an assessment from everything that affects
+// its result: the provider, its options (including the model), the prompt
+// version and the prompt itself, which embeds the documentation and code.
+func Key(provider string, opts config.ProviderOptions, prompt string) (string, error) {
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode provider options: %w", err)
+	}
+
+	h := sha256.New()
+	writeField(h, "provider", provider)
+	writeField(h, "options", string(optsJSON))
+	writeField(h, "version", assessor.PromptVersion)
+	writeField(h, "prompt", prompt)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeField writes a length-prefixed field, so that no two different
+// sequences of fields hash the same.
+func writeField(w io.Writer, name, value string) {
+	fmt.Fprintf(w, "%s %d\n%s\n", strings.ReplaceAll(name, "\n", " "), len(value), value)
+}
+
+// Assessor returns cached results when available and caches the results of
+// the DocAssessor it wraps otherwise.
+type Assessor struct {
+	inner    assessor.DocAssessor
+	store    *Store
+	provider string
+	opts     config.ProviderOptions
+}
+
+// NewAssessor wraps inner, which must be the assessor for provider and opts,
+// with a cache backed by store.
+func NewAssessor(inner assessor.DocAssessor, store *Store, provider string, opts config.ProviderOptions) *Assessor {
+	return &Assessor{inner: inner, store: store, provider: provider, opts: opts}
+}
+
+// Assess returns the cached result for the request's prompt, or assesses the
+// request and caches the result. Failed assessments are not cached.
+func (a *Assessor) Assess(ctx context.Context, req assessor.Request) (*assessor.AssessmentResult, error) {
+	prompt, err := req.RenderPrompt()
+	if err != nil {
+		return nil, err
+	}
+	key, err := Key(a.provider, a.opts, prompt)
+	if err != nil {
+		return nil, err
+	}
+	if result, ok := a.store.Get(key); ok {
+		return result, nil
+	}
+
+	result, err := a.inner.Assess(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// A failure to write the cache only costs a request next time, so it
+	// doesn't fail the check.
+	_ = a.store.Put(key, result)
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/cache"
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAssessor returns result, or err if set, and counts its calls.
+type countingAssessor struct {
+	result *assessor.AssessmentResult
+	err    error
+	calls  int
+}
+
+func (a *countingAssessor) Assess(ctx context.Context, req assessor.Request) (*assessor.AssessmentResult, error) {
+	a.calls++
+	return a.result, a.err
+}
+
+func TestAssessor(t *testing.T) {
+	store := cache.NewStore(filepath.Join(t.TempDir(), "cache"))
+	opts := config.ProviderOptions{Model: "gemini-2.5-pro"}
+	inner := &countingAssessor{result: &assessor.AssessmentResult{
+		IsInSync: false,
+		Reason:   "id is undocumented",
+		Findings: []assessor.Finding{{Category: assessor.CategoryMissingParam, Description: "id"}},
+	}}
+	a := cache.NewAssessor(inner, store, "gemini", opts)
+	code := map[string]string{"a.go": "package a", "b.go": "package b"}
+
+	first, err := a.Assess(context.Background(), assessor.Request{DocContent: "# Docs", CodeContents: code})
+	require.NoError(t, err)
+	second, err := a.Assess(context.Background(), assessor.Request{DocContent: "# Docs", CodeContents: map[string]string{"b.go": "package b", "a.go": "package a"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, inner.calls, "unchanged contents should be served from the cache")
+	assert.Equal(t, first, second)
+
+	_, err = a.Assess(context.Background(), assessor.Request{DocContent: "# Docs v2", CodeContents: code})
+	require.NoError(t, err)
+	_, err = a.Assess(context.Background(), assessor.Request{DocContent: "# Docs", CodeContents: map[string]string{"a.go": "package a", "b.go": "package b // changed"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, inner.calls, "changed contents should miss the cache")
+
+	other := cache.NewAssessor(inner, store, "gemini", config.ProviderOptions{Model: "gemini-2.5-flash"})
+	_, err = other.Assess(context.Background(), assessor.Request{DocContent: "# Docs", CodeContents: code})
+	require.NoError(t, err)
+	assert.Equal(t, 4, inner.calls, "a different model should miss the cache")
+
+	stats, err := store.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Entries)
+	assert.Positive(t, stats.Bytes)
+}
+
+func TestAssessor_ErrorsAreNotCached(t *testing.T) {
+	store := cache.NewStore(t.TempDir())
+	inner := &countingAssessor{err: errors.New("rate limited")}
+	a := cache.NewAssessor(inner, store, "openai", config.ProviderOptions{})
+
+	for i := 0; i < 2; i++ {
+		_, err := a.Assess(context.Background(), assessor.Request{DocContent: "# Docs", CodeContents: nil})
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 2, inner.calls)
+
+	stats, err := store.Stats()
+	require.NoError(t, err)
+	assert.Zero(t, stats.Entries)
+}
+
+func TestStore_Clear(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	store := cache.NewStore(dir)
+
+	n, err := store.Clear()
+	require.NoError(t, err, "clearing a missing cache should succeed")
+	assert.Zero(t, n)
+
+	require.NoError(t, store.Put("one", &assessor.AssessmentResult{IsInSync: true}))
+	require.NoError(t, store.Put("two", &assessor.AssessmentResult{IsInSync: true}))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not an entry"), 0644))
+
+	n, err = store.Clear()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	_, ok := store.Get("one")
+	assert.False(t, ok)
+	assert.FileExists(t, filepath.Join(dir, "README"))
+}
+
+func TestStore_GetCorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	store := cache.NewStore(dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte("{"), 0644))
+
+	_, ok := store.Get("bad")
+	assert.False(t, ok)
+}
+
+func TestKey(t *testing.T) {
+	key := func(provider string, opts config.ProviderOptions, prompt string) string {
+		k, err := cache.Key(provider, opts, prompt)
+		require.NoError(t, err)
+		return k
+	}
+	base := key("gemini", config.ProviderOptions{}, "prompt")
+
+	assert.Equal(t, base, key("gemini", config.ProviderOptions{}, "prompt"))
+	assert.NotEqual(t, base, key("openai", config.ProviderOptions{}, "prompt"))
+	assert.NotEqual(t, base, key("gemini", config.ProviderOptions{Model: "m"}, "prompt"))
+	assert.NotEqual(t, base, key("gemini", config.ProviderOptions{}, "prompt 2"))
+	// Moving text between fields must change the key.
+	assert.NotEqual(t, key("gemini", config.ProviderOptions{}, "prompt"), key("geminiprompt", config.ProviderOptions{}, ""))
+}
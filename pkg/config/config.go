@@ -2,20 +2,343 @@ package config
 
 import (
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Version  int    `yaml:"version"`
-	Provider string `yaml:"provider"`
+	Version         int             `yaml:"version"`
+	Provider        string          `yaml:"provider"`
+	ProviderOptions ProviderOptions `yaml:"provider_options,omitempty"`
+	// Concurrency is the number of rules checked in parallel. The
+	// --concurrency flag overrides it.
+	Concurrency int `yaml:"concurrency,omitempty"`
+	// Retry controls how failed provider requests are retried.
+	Retry RetryOptions `yaml:"retry,omitempty"`
+	// RateLimit caps the rate of provider requests across all rules.
+	RateLimit RateLimitOptions `yaml:"rate_limit,omitempty"`
+	// TokenBudget limits the size of the prompt sent for each rule.
+	TokenBudget TokenBudget `yaml:"token_budget,omitempty"`
+	// Prompt customises the prompt sent to the provider.
+	Prompt PromptOptions `yaml:"prompt,omitempty"`
+	// MinConfidence is the confidence, from 0 to 1, below which drift is
+	// reported as a warning instead of failing the check. Zero means drift
+	// always fails the check.
+	MinConfidence float64 `yaml:"min_confidence,omitempty"`
+	// Severity is the default severity of the rules' drift.
+	Severity string `yaml:"severity,omitempty"`
 	Rules    []Rule `yaml:"rules"`
 }
 
+// Severities of drift.
+const (
+	// SeverityError is drift that fails the check by default.
+	SeverityError = "error"
+	// SeverityWarning is drift that only fails the check with
+	// --fail-on=warning or --fail-on=info.
+	SeverityWarning = "warning"
+	// SeverityInfo is drift that only fails the check with --fail-on=info.
+	SeverityInfo = "info"
+)
+
+// Severities lists the severities of drift, from the most to the least
+// severe.
+var Severities = []string{SeverityError, SeverityWarning, SeverityInfo}
+
+// IsSeverity reports whether s is one of Severities.
+func IsSeverity(s string) bool {
+	for _, severity := range Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// SeverityAtLeast reports whether severity is at least as severe as
+// threshold. An empty severity means SeverityError.
+func SeverityAtLeast(severity, threshold string) bool {
+	rank := func(s string) int {
+		for i, severity := range Severities {
+			if s == severity {
+				return i
+			}
+		}
+		return 0
+	}
+	return rank(severity) <= rank(threshold)
+}
+
+// PromptOptions customises the prompt sent to the provider.
+type PromptOptions struct {
+	// Template is a Go text/template that replaces the built-in prompt.
+	Template string `yaml:"template,omitempty"`
+	// Instructions are appended to the prompt, e.g. house conventions for
+	// what should not be reported as drift.
+	Instructions string `yaml:"instructions,omitempty"`
+}
+
+// RetryOptions controls retries of rate-limited, overloaded or timed-out
+// provider requests. Unset fields fall back to the defaults.
+type RetryOptions struct {
+	// MaxAttempts is the total number of attempts per rule, including the
+	// first one. Defaults to 3; 1 disables retries.
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+	// InitialBackoff is the delay before the first retry. It doubles with
+	// each retry. Defaults to 1s.
+	InitialBackoff time.Duration `yaml:"initial_backoff,omitempty"`
+	// MaxBackoff caps the delay between retries. Defaults to 30s.
+	MaxBackoff time.Duration `yaml:"max_backoff,omitempty"`
+}
+
+// RateLimitOptions configures the client-side request limiter.
+type RateLimitOptions struct {
+	// RequestsPerMinute is the maximum number of provider requests per
+	// minute, shared by all rules. Zero means unlimited.
+	RequestsPerMinute int `yaml:"requests_per_minute,omitempty"`
+}
+
+// ProviderOptions holds settings passed to the selected provider.
+type ProviderOptions struct {
+	// BaseURL is the endpoint of a self-hosted or OpenAI-compatible API.
+	BaseURL string `yaml:"base_url,omitempty"`
+	// Model is the name of the model to request from the provider.
+	Model string `yaml:"model,omitempty"`
+	// Temperature controls sampling randomness. Lower values give more
+	// deterministic verdicts. Unset means the provider's default.
+	Temperature *float32 `yaml:"temperature,omitempty"`
+	// MaxOutputTokens caps the length of the model's answer.
+	MaxOutputTokens int `yaml:"max_output_tokens,omitempty"`
+	// Seed requests reproducible sampling from providers that support it.
+	Seed *int `yaml:"seed,omitempty"`
+	// Timeout bounds a single request to the provider, e.g. "30s".
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Fixtures is the directory of recorded responses served by the replay
+	// provider, or the file of scripted outcomes of the dummy provider.
+	Fixtures string `yaml:"fixtures,omitempty"`
+	// Consensus configures the voters of the consensus provider.
+	Consensus *ConsensusOptions `yaml:"consensus,omitempty"`
+}
+
+// Strategies for combining the votes of the consensus provider.
+const (
+	// ConsensusMajority reports drift when at least half of the votes do.
+	ConsensusMajority = "majority"
+	// ConsensusUnanimous reports drift only when every vote does.
+	ConsensusUnanimous = "unanimous"
+	// ConsensusWeighted reports drift when the votes for drift carry at
+	// least half of the total weight.
+	ConsensusWeighted = "weighted"
+)
+
+// ConsensusOptions configures the consensus provider, which asks several
+// providers, models or samples of one model and combines their votes.
+type ConsensusOptions struct {
+	// Strategy is ConsensusMajority (the default), ConsensusUnanimous or
+	// ConsensusWeighted.
+	Strategy string `yaml:"strategy,omitempty"`
+	// Voters are the providers that vote.
+	Voters []Voter `yaml:"voters"`
+}
+
+// Voter is a provider that votes in a consensus.
+type Voter struct {
+	// Name identifies the voter in dissenting votes. Defaults to the
+	// provider and model.
+	Name            string          `yaml:"name,omitempty"`
+	Provider        string          `yaml:"provider"`
+	ProviderOptions ProviderOptions `yaml:"provider_options,omitempty"`
+	// Samples is the number of times the voter is asked, each answer
+	// counting as one vote. Defaults to 1.
+	Samples int `yaml:"samples,omitempty"`
+	// Weight is the weight of each of the voter's votes with the weighted
+	// strategy. Defaults to 1.
+	Weight float64 `yaml:"weight,omitempty"`
+}
+
+// DummyOutcome scripts the answer of the dummy provider for a rule.
+type DummyOutcome struct {
+	// InSync is the verdict.
+	InSync bool   `yaml:"in_sync"`
+	Reason string `yaml:"reason,omitempty"`
+	// Confidence is the confidence of the verdict, from 0 to 1. Unset means
+	// the verdict has no confidence.
+	Confidence *float64 `yaml:"confidence,omitempty"`
+	// Findings use the same fields as the findings of an assessment, e.g.
+	// category and description.
+	Findings []map[string]interface{} `yaml:"findings,omitempty"`
+	// Error, if set, makes the assessment fail with this message.
+	Error string `yaml:"error,omitempty"`
+	// Delay is how long the assessment takes.
+	Delay time.Duration `yaml:"delay,omitempty"`
+}
+
+// Strategies for rules whose prompt exceeds their token budget.
+const (
+	// OversizeFail fails the rule with a "too large" error.
+	OversizeFail = "fail"
+	// OversizeSplit assesses the rule's code files in chunks that fit the
+	// budget and merges the findings.
+	OversizeSplit = "split"
+)
+
+// TokenBudget limits the estimated size of the prompt sent for a rule.
+type TokenBudget struct {
+	// MaxTokens is the maximum estimated number of prompt tokens. Zero
+	// means unlimited.
+	MaxTokens int `yaml:"max_tokens,omitempty"`
+	// Oversize is OversizeFail (the default) or OversizeSplit.
+	Oversize string `yaml:"oversize,omitempty"`
+}
+
 type Rule struct {
 	Name string   `yaml:"name"`
 	Code []string `yaml:"code"`
 	Docs []string `yaml:"docs"`
+	// ExcludeCode and ExcludeDocs are glob patterns of files left out of
+	// Code and Docs, e.g. tests or generated files. Patterns of Code and
+	// Docs prefixed with "!" are left out too.
+	ExcludeCode []string `yaml:"exclude_code,omitempty"`
+	ExcludeDocs []string `yaml:"exclude_docs,omitempty"`
+	// Provider and ProviderOptions override the top-level settings for this rule.
+	Provider        string           `yaml:"provider,omitempty"`
+	ProviderOptions *ProviderOptions `yaml:"provider_options,omitempty"`
+	// TokenBudget overrides the top-level token budget for this rule.
+	TokenBudget *TokenBudget `yaml:"token_budget,omitempty"`
+	// Prompt overrides the top-level prompt template for this rule and adds
+	// to its instructions.
+	Prompt *PromptOptions `yaml:"prompt,omitempty"`
+	// MinConfidence overrides the top-level minimum confidence for this
+	// rule.
+	MinConfidence *float64 `yaml:"min_confidence,omitempty"`
+	// Severity overrides the top-level severity of the rule's drift.
+	Severity string `yaml:"severity,omitempty"`
+	// Dummy scripts the outcome of this rule when it is checked with the
+	// dummy provider.
+	Dummy *DummyOutcome `yaml:"dummy,omitempty"`
+}
+
+// CodePatterns returns the glob patterns of the rule's code files, with its
+// ExcludeCode patterns prefixed with "!".
+func (r Rule) CodePatterns() []string {
+	return withExclusions(r.Code, r.ExcludeCode)
+}
+
+// DocPatterns returns the glob patterns of the rule's doc files, with its
+// ExcludeDocs patterns prefixed with "!".
+func (r Rule) DocPatterns() []string {
+	return withExclusions(r.Docs, r.ExcludeDocs)
+}
+
+func withExclusions(patterns, excludes []string) []string {
+	if len(excludes) == 0 {
+		return patterns
+	}
+	all := append([]string{}, patterns...)
+	for _, exclude := range excludes {
+		all = append(all, "!"+exclude)
+	}
+	return all
+}
+
+// ProviderFor returns the provider and options used to assess a rule. Options
+// set on the rule override the top-level ones. When the rule switches to a
+// different provider, the top-level options are not inherited, since model
+// names and endpoints don't carry over between providers.
+func (c *Config) ProviderFor(rule Rule) (string, ProviderOptions) {
+	provider := c.Provider
+	opts := c.ProviderOptions
+	if rule.Provider != "" && rule.Provider != c.Provider {
+		provider = rule.Provider
+		opts = ProviderOptions{}
+	}
+	if rule.ProviderOptions != nil {
+		opts = opts.Merge(*rule.ProviderOptions)
+	}
+	return provider, opts
+}
+
+// TokenBudgetFor returns the token budget of a rule: the top-level budget
+// with the fields set on the rule overridden.
+func (c *Config) TokenBudgetFor(rule Rule) TokenBudget {
+	budget := c.TokenBudget
+	if rule.TokenBudget != nil {
+		if rule.TokenBudget.MaxTokens != 0 {
+			budget.MaxTokens = rule.TokenBudget.MaxTokens
+		}
+		if rule.TokenBudget.Oversize != "" {
+			budget.Oversize = rule.TokenBudget.Oversize
+		}
+	}
+	return budget
+}
+
+// PromptFor returns the prompt settings of a rule. A template set on the rule
+// replaces the top-level one, and instructions set on the rule are appended
+// to the top-level ones.
+func (c *Config) PromptFor(rule Rule) PromptOptions {
+	opts := c.Prompt
+	if rule.Prompt != nil {
+		if rule.Prompt.Template != "" {
+			opts.Template = rule.Prompt.Template
+		}
+		if rule.Prompt.Instructions != "" {
+			opts.Instructions = strings.TrimSpace(strings.TrimSpace(opts.Instructions) + "\n" + rule.Prompt.Instructions)
+		}
+	}
+	return opts
+}
+
+// MinConfidenceFor returns the minimum confidence of a rule's drift: the
+// rule's own setting if it has one, or the top-level one.
+func (c *Config) MinConfidenceFor(rule Rule) float64 {
+	if rule.MinConfidence != nil {
+		return *rule.MinConfidence
+	}
+	return c.MinConfidence
+}
+
+// SeverityFor returns the severity of a rule's drift: the rule's own severity
+// if it has one, then the top-level one, and SeverityError by default.
+func (c *Config) SeverityFor(rule Rule) string {
+	if rule.Severity != "" {
+		return rule.Severity
+	}
+	if c.Severity != "" {
+		return c.Severity
+	}
+	return SeverityError
+}
+
+// Merge returns a copy of o with every field that is set in override replaced.
+func (o ProviderOptions) Merge(override ProviderOptions) ProviderOptions {
+	if override.BaseURL != "" {
+		o.BaseURL = override.BaseURL
+	}
+	if override.Model != "" {
+		o.Model = override.Model
+	}
+	if override.Temperature != nil {
+		o.Temperature = override.Temperature
+	}
+	if override.MaxOutputTokens != 0 {
+		o.MaxOutputTokens = override.MaxOutputTokens
+	}
+	if override.Seed != nil {
+		o.Seed = override.Seed
+	}
+	if override.Timeout != 0 {
+		o.Timeout = override.Timeout
+	}
+	if override.Fixtures != "" {
+		o.Fixtures = override.Fixtures
+	}
+	if override.Consensus != nil {
+		o.Consensus = override.Consensus
+	}
+	return o
 }
 
 // Load finds and unmarshals a .drift.yaml file
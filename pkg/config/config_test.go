@@ -3,8 +3,10 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/driftee-ai/drift/pkg/config"
 )
@@ -104,6 +106,207 @@ rules:
 	}
 }
 
+func TestLoad_ProviderOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".drift.yaml")
+
+	testConfig := `
+version: 1
+provider: openai
+provider_options:
+  model: gpt-4o-mini
+  temperature: 0
+  max_output_tokens: 512
+  seed: 7
+  timeout: 45s
+rules: []
+`
+	if err := os.WriteFile(configPath, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("Failed to write test config file: %v", err)
+	}
+
+	loadedConfig, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	opts := loadedConfig.ProviderOptions
+	if opts.Model != "gpt-4o-mini" {
+		t.Errorf("Expected model 'gpt-4o-mini', got '%s'", opts.Model)
+	}
+	if opts.Temperature == nil || *opts.Temperature != 0 {
+		t.Errorf("Expected temperature 0, got %v", opts.Temperature)
+	}
+	if opts.MaxOutputTokens != 512 {
+		t.Errorf("Expected max_output_tokens 512, got %d", opts.MaxOutputTokens)
+	}
+	if opts.Seed == nil || *opts.Seed != 7 {
+		t.Errorf("Expected seed 7, got %v", opts.Seed)
+	}
+	if opts.Timeout != 45*time.Second {
+		t.Errorf("Expected timeout 45s, got %s", opts.Timeout)
+	}
+}
+
+func TestProviderFor(t *testing.T) {
+	temperature := float32(0)
+	cfg := &config.Config{
+		Provider: "gemini",
+		ProviderOptions: config.ProviderOptions{
+			Model:   "gemini-2.5-flash",
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		rule         config.Rule
+		wantProvider string
+		wantOpts     config.ProviderOptions
+	}{
+		{
+			name:         "no override uses top-level settings",
+			rule:         config.Rule{Name: "plain"},
+			wantProvider: "gemini",
+			wantOpts:     config.ProviderOptions{Model: "gemini-2.5-flash", Timeout: 30 * time.Second},
+		},
+		{
+			name:         "model override keeps other top-level options",
+			rule:         config.Rule{Name: "strong", ProviderOptions: &config.ProviderOptions{Model: "gemini-2.5-pro", Temperature: &temperature}},
+			wantProvider: "gemini",
+			wantOpts:     config.ProviderOptions{Model: "gemini-2.5-pro", Temperature: &temperature, Timeout: 30 * time.Second},
+		},
+		{
+			name:         "provider override drops top-level options",
+			rule:         config.Rule{Name: "other", Provider: "openai", ProviderOptions: &config.ProviderOptions{Model: "gpt-4o-mini"}},
+			wantProvider: "openai",
+			wantOpts:     config.ProviderOptions{Model: "gpt-4o-mini"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, opts := cfg.ProviderFor(tt.rule)
+			if provider != tt.wantProvider {
+				t.Errorf("provider = %q, want %q", provider, tt.wantProvider)
+			}
+			if !reflect.DeepEqual(opts, tt.wantOpts) {
+				t.Errorf("options = %+v, want %+v", opts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestTokenBudgetFor(t *testing.T) {
+	cfg := &config.Config{TokenBudget: config.TokenBudget{MaxTokens: 100000}}
+
+	if got := cfg.TokenBudgetFor(config.Rule{Name: "plain"}); got != cfg.TokenBudget {
+		t.Errorf("TokenBudgetFor() = %+v, want %+v", got, cfg.TokenBudget)
+	}
+
+	rule := config.Rule{Name: "big", TokenBudget: &config.TokenBudget{Oversize: config.OversizeSplit}}
+	want := config.TokenBudget{MaxTokens: 100000, Oversize: config.OversizeSplit}
+	if got := cfg.TokenBudgetFor(rule); got != want {
+		t.Errorf("TokenBudgetFor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPromptFor(t *testing.T) {
+	cfg := &config.Config{Prompt: config.PromptOptions{
+		Template:     "global template",
+		Instructions: "Examples are illustrative.",
+	}}
+
+	if got := cfg.PromptFor(config.Rule{Name: "plain"}); got != cfg.Prompt {
+		t.Errorf("PromptFor() = %+v, want %+v", got, cfg.Prompt)
+	}
+
+	rule := config.Rule{Name: "api", Prompt: &config.PromptOptions{
+		Template:     "rule template",
+		Instructions: "Ignore the changelog.",
+	}}
+	want := config.PromptOptions{
+		Template:     "rule template",
+		Instructions: "Examples are illustrative.\nIgnore the changelog.",
+	}
+	if got := cfg.PromptFor(rule); got != want {
+		t.Errorf("PromptFor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMinConfidenceFor(t *testing.T) {
+	cfg := &config.Config{MinConfidence: 0.7}
+	zero, strict := 0.0, 0.9
+
+	tests := []struct {
+		name string
+		rule config.Rule
+		want float64
+	}{
+		{name: "inherited", rule: config.Rule{}, want: 0.7},
+		{name: "overridden", rule: config.Rule{MinConfidence: &strict}, want: 0.9},
+		{name: "disabled", rule: config.Rule{MinConfidence: &zero}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.MinConfidenceFor(tt.rule); got != tt.want {
+				t.Errorf("MinConfidenceFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityFor(t *testing.T) {
+	if got := (&config.Config{}).SeverityFor(config.Rule{}); got != config.SeverityError {
+		t.Errorf("SeverityFor() = %q, want %q", got, config.SeverityError)
+	}
+	cfg := &config.Config{Severity: config.SeverityWarning}
+	if got := cfg.SeverityFor(config.Rule{}); got != config.SeverityWarning {
+		t.Errorf("SeverityFor() = %q, want %q", got, config.SeverityWarning)
+	}
+	if got := cfg.SeverityFor(config.Rule{Severity: config.SeverityInfo}); got != config.SeverityInfo {
+		t.Errorf("SeverityFor() = %q, want %q", got, config.SeverityInfo)
+	}
+}
+
+func TestRulePatterns(t *testing.T) {
+	rule := config.Rule{
+		Code:        []string{"src/**/*.go", "!src/gen/**"},
+		Docs:        []string{"docs/**/*.md"},
+		ExcludeCode: []string{"**/*_test.go"},
+	}
+	if got, want := rule.CodePatterns(), []string{"src/**/*.go", "!src/gen/**", "!**/*_test.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CodePatterns() = %v, want %v", got, want)
+	}
+	if got, want := rule.DocPatterns(), []string{"docs/**/*.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DocPatterns() = %v, want %v", got, want)
+	}
+	if len(rule.Code) != 2 {
+		t.Errorf("CodePatterns() modified the rule's code patterns: %v", rule.Code)
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		severity, threshold string
+		want                bool
+	}{
+		{config.SeverityError, config.SeverityError, true},
+		{config.SeverityWarning, config.SeverityError, false},
+		{config.SeverityWarning, config.SeverityWarning, true},
+		{config.SeverityError, config.SeverityInfo, true},
+		{config.SeverityInfo, config.SeverityWarning, false},
+		{"", config.SeverityError, true},
+	}
+
+	for _, tt := range tests {
+		if got := config.SeverityAtLeast(tt.severity, tt.threshold); got != tt.want {
+			t.Errorf("SeverityAtLeast(%q, %q) = %v, want %v", tt.severity, tt.threshold, got, tt.want)
+		}
+	}
+}
+
 // Helper function to remove comments from the YAML string
 func removeComments(s string) string {
 	lines := strings.Split(s, "\n")
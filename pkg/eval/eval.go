@@ -0,0 +1,200 @@
+// Package eval measures how well a provider detects drift on a corpus of
+// labelled test cases, such as testdata/e2e.
+//
+// A corpus is a directory with one subdirectory per label, each holding one
+// directory per case with its own .drift.yaml:
+//
+//	true_positives/<case>   drift that should be detected
+//	false_negatives/<case>  subtle drift that models tend to miss
+//	true_negatives/<case>   documentation in sync with the code
+//	false_positives/<case>  in-sync documentation that models tend to flag
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Labels maps each label directory to whether its cases contain drift.
+var Labels = map[string]bool{
+	"true_positives":  true,
+	"false_negatives": true,
+	"true_negatives":  false,
+	"false_positives": false,
+}
+
+// Case is a labelled test case.
+type Case struct {
+	// Name is the case's path relative to the corpus, e.g.
+	// "true_positives/missing_param_in_docs".
+	Name string `json:"name"`
+	// Dir is the directory of the case.
+	Dir string `json:"-"`
+	// WantDrift is whether the case's documentation is out of sync.
+	WantDrift bool `json:"want_drift"`
+}
+
+// ConfigPath returns the path of the case's configuration file.
+func (c Case) ConfigPath() string {
+	return filepath.Join(c.Dir, ".drift.yaml")
+}
+
+// Discover returns the cases of the corpus in root, sorted by name.
+// Directories whose name isn't a known label are ignored.
+func Discover(root string) ([]Case, error) {
+	var cases []Case
+	for label, wantDrift := range Labels {
+		entries, err := os.ReadDir(filepath.Join(root, label))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			cases = append(cases, Case{
+				Name:      label + "/" + e.Name(),
+				Dir:       filepath.Join(root, label, e.Name()),
+				WantDrift: wantDrift,
+			})
+		}
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no test cases found in %s", root)
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+}
+
+// Result is the verdict of the provider on a case.
+type Result struct {
+	Case
+	// GotDrift is whether the provider reported drift in any rule of the
+	// case. It is meaningless when Error is set.
+	GotDrift bool `json:"got_drift"`
+	// Reason explains the provider's verdict.
+	Reason string `json:"reason,omitempty"`
+	// Error is set when the case could not be evaluated.
+	Error string `json:"error,omitempty"`
+}
+
+// Agrees reports whether the provider's verdict matches the label.
+func (r Result) Agrees() bool {
+	return r.Error == "" && r.GotDrift == r.WantDrift
+}
+
+// Summary aggregates the results of an evaluation, treating drift as the
+// positive class. Cases that errored are not part of the metrics.
+type Summary struct {
+	Provider       string   `json:"provider"`
+	Model          string   `json:"model,omitempty"`
+	Cases          int      `json:"cases"`
+	TruePositives  int      `json:"true_positives"`
+	FalsePositives int      `json:"false_positives"`
+	TrueNegatives  int      `json:"true_negatives"`
+	FalseNegatives int      `json:"false_negatives"`
+	Errors         int      `json:"errors"`
+	Precision      float64  `json:"precision"`
+	Recall         float64  `json:"recall"`
+	F1             float64  `json:"f1"`
+	Results        []Result `json:"results"`
+}
+
+// Summarize computes the confusion matrix and metrics of results.
+func Summarize(provider, model string, results []Result) *Summary {
+	s := &Summary{Provider: provider, Model: model, Cases: len(results), Results: results}
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			s.Errors++
+		case r.WantDrift && r.GotDrift:
+			s.TruePositives++
+		case !r.WantDrift && r.GotDrift:
+			s.FalsePositives++
+		case !r.WantDrift && !r.GotDrift:
+			s.TrueNegatives++
+		default:
+			s.FalseNegatives++
+		}
+	}
+	s.Precision = ratio(s.TruePositives, s.TruePositives+s.FalsePositives)
+	s.Recall = ratio(s.TruePositives, s.TruePositives+s.FalseNegatives)
+	if s.Precision+s.Recall > 0 {
+		s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+	return s
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// WriteText renders the summary in a human-readable format, listing the
+// cases where the provider disagreed with the label.
+func WriteText(w io.Writer, s *Summary) error {
+	model := ""
+	if s.Model != "" {
+		model = " (" + s.Model + ")"
+	}
+	lines := []string{fmt.Sprintf("Evaluated %d cases with %s%s", s.Cases, s.Provider, model)}
+
+	var disagreements, errors []string
+	for _, r := range s.Results {
+		switch {
+		case r.Error != "":
+			errors = append(errors, fmt.Sprintf("  - %s: %s", r.Name, r.Error))
+		case !r.Agrees():
+			line := fmt.Sprintf("  - %s: expected %s, got %s", r.Name, verdict(r.WantDrift), verdict(r.GotDrift))
+			if r.Reason != "" {
+				line += " (" + r.Reason + ")"
+			}
+			disagreements = append(disagreements, line)
+		}
+	}
+	if len(disagreements) > 0 {
+		lines = append(lines, "Disagreements:")
+		lines = append(lines, disagreements...)
+	}
+	if len(errors) > 0 {
+		lines = append(lines, "Errors:")
+		lines = append(lines, errors...)
+	}
+	lines = append(lines,
+		fmt.Sprintf("True positives: %d, false positives: %d, true negatives: %d, false negatives: %d, errors: %d",
+			s.TruePositives, s.FalsePositives, s.TrueNegatives, s.FalseNegatives, s.Errors),
+		fmt.Sprintf("Precision: %.2f", s.Precision),
+		fmt.Sprintf("Recall: %.2f", s.Recall),
+		fmt.Sprintf("F1: %.2f", s.F1),
+	)
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON renders the summary as a single indented JSON document.
+func WriteJSON(w io.Writer, s *Summary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+func verdict(drift bool) string {
+	if drift {
+		return "drift"
+	}
+	return "in sync"
+}
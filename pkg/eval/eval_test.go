@@ -0,0 +1,134 @@
+package eval_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/eval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	cases, err := eval.Discover("../../testdata/e2e")
+	require.NoError(t, err)
+
+	var names []string
+	wantDrift := map[string]bool{}
+	for _, c := range cases {
+		names = append(names, c.Name)
+		wantDrift[c.Name] = c.WantDrift
+		assert.FileExists(t, c.ConfigPath())
+	}
+	assert.Equal(t, []string{
+		"false_negatives/subtle_drift_example",
+		"false_positives/cosmetic_diff_example",
+		"true_negatives/in_sync_example",
+		"true_positives/missing_param_in_docs",
+	}, names)
+	assert.True(t, wantDrift["true_positives/missing_param_in_docs"])
+	assert.True(t, wantDrift["false_negatives/subtle_drift_example"])
+	assert.False(t, wantDrift["true_negatives/in_sync_example"])
+	assert.False(t, wantDrift["false_positives/cosmetic_diff_example"])
+}
+
+func TestDiscover_IgnoresUnknownLabels(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "drafts", "wip"), 0755))
+
+	_, err := eval.Discover(root)
+	assert.Error(t, err, "a corpus without labelled cases should be rejected")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "true_positives", "case"), 0755))
+	cases, err := eval.Discover(root)
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.Equal(t, "true_positives/case", cases[0].Name)
+}
+
+// sampleResults returns two true positives, one false positive, one true
+// negative, one false negative and one error.
+func sampleResults() []eval.Result {
+	result := func(name string, want, got bool) eval.Result {
+		return eval.Result{Case: eval.Case{Name: name, WantDrift: want}, GotDrift: got}
+	}
+	fp := result("false_positives/cosmetic", false, true)
+	fp.Reason = "wording differs"
+	failed := result("true_positives/broken", true, false)
+	failed.Error = "Rule: rate limited"
+	return []eval.Result{
+		result("true_positives/a", true, true),
+		result("true_positives/b", true, true),
+		fp,
+		result("true_negatives/a", false, false),
+		result("false_negatives/subtle", true, false),
+		failed,
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := eval.Summarize("gemini", "gemini-2.5-pro", sampleResults())
+
+	assert.Equal(t, 6, s.Cases)
+	assert.Equal(t, 2, s.TruePositives)
+	assert.Equal(t, 1, s.FalsePositives)
+	assert.Equal(t, 1, s.TrueNegatives)
+	assert.Equal(t, 1, s.FalseNegatives)
+	assert.Equal(t, 1, s.Errors)
+	assert.InDelta(t, 2.0/3, s.Precision, 1e-9)
+	assert.InDelta(t, 2.0/3, s.Recall, 1e-9)
+	assert.InDelta(t, 2.0/3, s.F1, 1e-9)
+}
+
+func TestSummarize_NoPositives(t *testing.T) {
+	s := eval.Summarize("dummy", "", []eval.Result{
+		{Case: eval.Case{Name: "true_negatives/a"}},
+	})
+	assert.Zero(t, s.Precision)
+	assert.Zero(t, s.Recall)
+	assert.Zero(t, s.F1)
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, eval.WriteText(&buf, eval.Summarize("gemini", "gemini-2.5-pro", sampleResults())))
+
+	want := `Evaluated 6 cases with gemini (gemini-2.5-pro)
+Disagreements:
+  - false_positives/cosmetic: expected in sync, got drift (wording differs)
+  - false_negatives/subtle: expected drift, got in sync
+Errors:
+  - true_positives/broken: Rule: rate limited
+True positives: 2, false positives: 1, true negatives: 1, false negatives: 1, errors: 1
+Precision: 0.67
+Recall: 0.67
+F1: 0.67
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, eval.WriteJSON(&buf, eval.Summarize("gemini", "", sampleResults())))
+
+	var got struct {
+		Provider string  `json:"provider"`
+		F1       float64 `json:"f1"`
+		Results  []struct {
+			Name      string `json:"name"`
+			WantDrift bool   `json:"want_drift"`
+			GotDrift  bool   `json:"got_drift"`
+			Error     string `json:"error"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, "gemini", got.Provider)
+	assert.InDelta(t, 0.67, got.F1, 0.01)
+	require.Len(t, got.Results, 6)
+	assert.Equal(t, "false_positives/cosmetic", got.Results[2].Name)
+	assert.True(t, got.Results[2].GotDrift)
+	assert.Equal(t, "Rule: rate limited", got.Results[5].Error)
+}
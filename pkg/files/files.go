@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
 
 // FindFiles takes a list of glob patterns and returns a list of matching file paths.
+// Files matching a pattern prefixed with "!" are left out.
 func FindFiles(patterns []string) ([]string, error) {
 	var matchingFiles []string
 	seen := make(map[string]bool)
 
+	patterns, excludes := SplitPatterns(patterns)
 	for _, pattern := range patterns {
 		// doublestar.Glob walks the file system and returns matching files
 		// Use os.DirFS(".") to glob the current directory
@@ -36,6 +39,11 @@ func FindFiles(patterns []string) ([]string, error) {
 			if info.IsDir() {
 				continue
 			}
+			if excluded, err := matchAny(excludes, match); err != nil {
+				return nil, err
+			} else if excluded {
+				continue
+			}
 
 			// Add to list if not already seen
 			if !seen[match] {
@@ -47,20 +55,64 @@ func FindFiles(patterns []string) ([]string, error) {
 	return matchingFiles, nil
 }
 
+// SplitPatterns separates the glob patterns prefixed with "!", which exclude
+// files, from the others. The "!" is removed from the exclusions.
+func SplitPatterns(patterns []string) (include, exclude []string) {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			exclude = append(exclude, strings.TrimPrefix(pattern, "!"))
+		} else {
+			include = append(include, pattern)
+		}
+	}
+	return include, exclude
+}
+
+// Match reports whether a path matches any of the glob patterns, and none of
+// the exclusions prefixed with "!".
+func Match(patterns []string, path string) (bool, error) {
+	include, exclude := SplitPatterns(patterns)
+	if matched, err := matchAny(include, path); err != nil || !matched {
+		return false, err
+	}
+	excluded, err := matchAny(exclude, path)
+	return !excluded, err
+}
+
+// matchAny reports whether a path matches any of the glob patterns.
+func matchAny(patterns []string, path string) (bool, error) {
+	for _, pattern := range patterns {
+		if match, err := doublestar.Match(pattern, path); err != nil {
+			return false, err
+		} else if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ReadAndConcatenate takes a list of file paths, reads each file, and returns a single string with all the content.s
-func ReadAndConcatenate(paths []string) (string, error) {
+// Regions of Markdown files marked with drift:ignore are left out, and returned as suppressions.
+func ReadAndConcatenate(paths []string) (string, []Suppression, error) {
 	var builder strings.Builder
+	var suppressions []Suppression
+	today := time.Now()
 	for _, path := range paths {
 		content, err := os.ReadFile(path)
 		if err != nil {
-			return "", fmt.Errorf("failed to read file %s: %w", path, err)
+			return "", nil, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		stripped, found, err := StripSuppressions(path, string(content), today)
+		if err != nil {
+			return "", nil, err
 		}
-		builder.WriteString(string(content))
+		suppressions = append(suppressions, found...)
+		builder.WriteString(stripped)
 		builder.WriteString("\n--- End of file: ")
 		builder.WriteString(path)
 		builder.WriteString(" ---\n")
 	}
-	return builder.String(), nil
+	return builder.String(), suppressions, nil
 }
 
 // ReadFiles takes a list of file paths and returns a map of file paths to their contents.
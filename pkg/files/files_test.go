@@ -88,6 +88,16 @@ func TestFindFiles(t *testing.T) {
 			patterns: []string{"nonexistent/*.txt"},
 			want:     []string{},
 		},
+		{
+			name:     "excluded pattern",
+			patterns: []string{"**/*.md", "!docs/**"},
+			want:     []string{"README.md"},
+		},
+		{
+			name:     "only excluded patterns",
+			patterns: []string{"!src/api/auth.go"},
+			want:     []string{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -108,6 +118,35 @@ func TestFindFiles(t *testing.T) {
 	}
 }
 
+func TestMatch(t *testing.T) {
+	patterns := []string{"src/**/*.go", "!**/*_test.go", "!src/gen/**"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "src/api/user.go", want: true},
+		{path: "src/api/user_test.go", want: false},
+		{path: "src/gen/user.go", want: false},
+		{path: "docs/api/users.md", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := files.Match(patterns, tt.path)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Match() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := files.Match([]string{"src/**/*.go", "![bad"}, "src/api/user.go"); err == nil {
+		t.Errorf("Match() with a bad exclusion got no error")
+	}
+}
+
 func TestReadAndConcatenate(t *testing.T) {
 	_, cleanup := setupTestFiles(t)
 	defer cleanup()
@@ -124,10 +163,13 @@ func TestReadAndConcatenate(t *testing.T) {
 # Users API
 --- End of file: docs/api/users.md ---
 `
-	got, err := files.ReadAndConcatenate(paths)
+	got, suppressions, err := files.ReadAndConcatenate(paths)
 	if err != nil {
 		t.Fatalf("ReadAndConcatenate() error = %v", err)
 	}
+	if len(suppressions) != 0 {
+		t.Errorf("ReadAndConcatenate() suppressions = %v, want none", suppressions)
+	}
 
 	if got != expectedContent {
 		t.Errorf("ReadAndConcatenate() got = %q, want %q", got, expectedContent)
@@ -0,0 +1,173 @@
+package files
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Suppression is a region of a Markdown file hidden from the assessor by a
+// drift:ignore marker.
+type Suppression struct {
+	// File and Line locate the marker that starts the region.
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Lines is the number of lines hidden, markers included.
+	Lines  int    `json:"lines"`
+	Reason string `json:"reason,omitempty"`
+	// Until is the date, as YYYY-MM-DD, after which the suppression
+	// expires.
+	Until string `json:"until,omitempty"`
+}
+
+// ExpiredSuppressionError is returned for a suppression whose until date has
+// passed. Unlike other read errors, it is a problem to fix in the repository
+// rather than a transient failure.
+type ExpiredSuppressionError struct {
+	File  string
+	Line  int
+	Until string
+}
+
+func (e *ExpiredSuppressionError) Error() string {
+	return fmt.Sprintf("%s:%d: suppression expired on %s", e.File, e.Line, e.Until)
+}
+
+// MarkerError is returned for a drift:ignore marker that is malformed or
+// misplaced. Like an expired suppression, it is a problem to fix in the
+// repository.
+type MarkerError struct {
+	File    string
+	Line    int
+	Problem string
+}
+
+func (e *MarkerError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Problem)
+}
+
+// markdownExts are the extensions of the files whose drift:ignore markers are
+// honoured.
+var markdownExts = map[string]bool{".md": true, ".mdx": true, ".markdown": true}
+
+var (
+	// markerPattern matches a drift:ignore marker on a line of its own.
+	markerPattern = regexp.MustCompile(`^<!--\s*drift:(ignore-start|ignore-end|ignore)\b(.*?)-->$`)
+	// attrPattern matches the key="value" attributes of a marker.
+	attrPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	// fencePattern matches the delimiters of fenced code blocks, in which
+	// markers are only examples.
+	fencePattern = regexp.MustCompile("^(```|~~~)")
+)
+
+// StripSuppressions hides the regions of a Markdown file marked with
+// drift:ignore comments:
+//
+//	<!-- drift:ignore reason="..." -->
+//	The block up to the next blank line is hidden.
+//
+//	<!-- drift:ignore-start until="2025-12-31" -->
+//	Everything up to the matching end marker is hidden.
+//	<!-- drift:ignore-end -->
+//
+// Hidden lines are blanked rather than removed, so that the line numbers of
+// findings stay right. Content of other files is returned as is. Suppressions
+// whose until date is before today fail, so that they are revisited.
+func StripSuppressions(path, content string, today time.Time) (string, []Suppression, error) {
+	if !markdownExts[strings.ToLower(filepath.Ext(path))] {
+		return content, nil, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	var suppressions []Suppression
+	var fence string
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if m := fencePattern.FindString(line); m != "" {
+			if fence == "" {
+				fence = m
+			} else if m == fence {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		m := markerPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		s := Suppression{File: path, Line: i + 1}
+		if err := parseMarkerAttrs(&s, m[2], today); err != nil {
+			return "", nil, err
+		}
+		var end int
+		switch m[1] {
+		case "ignore":
+			// Hide the next block, skipping the blank lines before it.
+			end = i + 1
+			for end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+				end++
+			}
+			for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+				end++
+			}
+		case "ignore-start":
+			end = i + 1
+			for end < len(lines) {
+				if m := markerPattern.FindStringSubmatch(strings.TrimSpace(lines[end])); m != nil {
+					if m[1] == "ignore-end" {
+						break
+					}
+					return "", nil, &MarkerError{File: path, Line: end + 1, Problem: fmt.Sprintf("drift:%s inside the drift:ignore-start at line %d", m[1], i+1)}
+				}
+				end++
+			}
+			if end == len(lines) {
+				return "", nil, &MarkerError{File: path, Line: i + 1, Problem: "drift:ignore-start without a drift:ignore-end"}
+			}
+			end++
+		case "ignore-end":
+			return "", nil, &MarkerError{File: path, Line: i + 1, Problem: "drift:ignore-end without a drift:ignore-start"}
+		}
+
+		for j := i; j < end; j++ {
+			lines[j] = ""
+		}
+		s.Lines = end - i
+		suppressions = append(suppressions, s)
+		i = end - 1
+	}
+	return strings.Join(lines, "\n"), suppressions, nil
+}
+
+// parseMarkerAttrs sets the reason and until date of a suppression from the
+// attributes of its marker. Anything else in the marker is an error, so that
+// a mistyped attribute such as until=2025-12-31 can't suppress forever.
+func parseMarkerAttrs(s *Suppression, attrs string, today time.Time) error {
+	if rest := strings.TrimSpace(attrPattern.ReplaceAllString(attrs, " ")); rest != "" {
+		return &MarkerError{File: s.File, Line: s.Line, Problem: fmt.Sprintf("unexpected %q in drift:ignore marker, want key=\"value\" attributes", rest)}
+	}
+	for _, m := range attrPattern.FindAllStringSubmatch(attrs, -1) {
+		switch m[1] {
+		case "reason":
+			s.Reason = m[2]
+		case "until":
+			until, err := time.Parse("2006-01-02", m[2])
+			if err != nil {
+				return &MarkerError{File: s.File, Line: s.Line, Problem: fmt.Sprintf("invalid until date %q, want YYYY-MM-DD", m[2])}
+			}
+			if until.Format("2006-01-02") < today.Format("2006-01-02") {
+				return &ExpiredSuppressionError{File: s.File, Line: s.Line, Until: m[2]}
+			}
+			s.Until = m[2]
+		default:
+			return &MarkerError{File: s.File, Line: s.Line, Problem: fmt.Sprintf("unknown drift:ignore attribute %q", m[1])}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package files_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/files"
+)
+
+var today = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestStripSuppressions(t *testing.T) {
+	tests := []struct {
+		name             string
+		path             string
+		content          string
+		wantContent      string
+		wantSuppressions []files.Suppression
+	}{
+		{
+			name:        "no markers",
+			path:        "README.md",
+			content:     "# Title\n\nText.\n",
+			wantContent: "# Title\n\nText.\n",
+		},
+		{
+			name:        "ignore hides the next block",
+			path:        "README.md",
+			content:     "# Title\n<!-- drift:ignore reason=\"planned\" -->\nNot yet.\nStill not.\n\nText.",
+			wantContent: "# Title\n\n\n\n\nText.",
+			wantSuppressions: []files.Suppression{
+				{File: "README.md", Line: 2, Lines: 3, Reason: "planned"},
+			},
+		},
+		{
+			name:        "ignore skips blank lines before the block",
+			path:        "README.md",
+			content:     "<!-- drift:ignore -->\n\nHidden.\n\nText.",
+			wantContent: "\n\n\n\nText.",
+			wantSuppressions: []files.Suppression{
+				{File: "README.md", Line: 1, Lines: 3},
+			},
+		},
+		{
+			name:        "region",
+			path:        "docs/guide.mdx",
+			content:     "Text.\n  <!-- drift:ignore-start reason=\"legacy\" until=\"2025-06-01\" -->\nOld.\n\nOlder.\n<!-- drift:ignore-end -->\nMore text.",
+			wantContent: "Text.\n\n\n\n\n\nMore text.",
+			wantSuppressions: []files.Suppression{
+				{File: "docs/guide.mdx", Line: 2, Lines: 5, Reason: "legacy", Until: "2025-06-01"},
+			},
+		},
+		{
+			name:        "markers in code fences are examples",
+			path:        "README.md",
+			content:     "```html\n<!-- drift:ignore-start -->\n```\nText.",
+			wantContent: "```html\n<!-- drift:ignore-start -->\n```\nText.",
+		},
+		{
+			name:        "markers must be alone on their line",
+			path:        "README.md",
+			content:     "Text <!-- drift:ignore -->\nMore text.",
+			wantContent: "Text <!-- drift:ignore -->\nMore text.",
+		},
+		{
+			name:        "other files are left as is",
+			path:        "main.go",
+			content:     "// <!-- drift:ignore -->\npackage main",
+			wantContent: "// <!-- drift:ignore -->\npackage main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, suppressions, err := files.StripSuppressions(tt.path, tt.content, today)
+			if err != nil {
+				t.Fatalf("StripSuppressions() error = %v", err)
+			}
+			if got != tt.wantContent {
+				t.Errorf("StripSuppressions() got = %q, want %q", got, tt.wantContent)
+			}
+			if strings.Count(got, "\n") != strings.Count(tt.content, "\n") {
+				t.Errorf("StripSuppressions() changed the number of lines")
+			}
+			if !reflect.DeepEqual(suppressions, tt.wantSuppressions) {
+				t.Errorf("StripSuppressions() suppressions = %+v, want %+v", suppressions, tt.wantSuppressions)
+			}
+		})
+	}
+}
+
+func TestStripSuppressions_Expired(t *testing.T) {
+	_, _, err := files.StripSuppressions("README.md", "Text.\n<!-- drift:ignore until=\"2025-05-31\" -->\nText.", today)
+	var expired *files.ExpiredSuppressionError
+	if !errors.As(err, &expired) {
+		t.Fatalf("StripSuppressions() error = %v, want an ExpiredSuppressionError", err)
+	}
+	want := files.ExpiredSuppressionError{File: "README.md", Line: 2, Until: "2025-05-31"}
+	if *expired != want {
+		t.Errorf("StripSuppressions() error = %+v, want %+v", *expired, want)
+	}
+}
+
+func TestStripSuppressions_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "expired",
+			content: "<!-- drift:ignore until=\"2025-05-31\" -->\nText.",
+			wantErr: "README.md:1: suppression expired on 2025-05-31",
+		},
+		{
+			name:    "invalid until date",
+			content: "<!-- drift:ignore until=\"next year\" -->\nText.",
+			wantErr: `README.md:1: invalid until date "next year", want YYYY-MM-DD`,
+		},
+		{
+			name:    "unknown attribute",
+			content: "<!-- drift:ignore untill=\"2026-01-01\" -->\nText.",
+			wantErr: `README.md:1: unknown drift:ignore attribute "untill"`,
+		},
+		{
+			name:    "unquoted attribute",
+			content: "<!-- drift:ignore until=2020-01-01 -->\nText.",
+			wantErr: `README.md:1: unexpected "until=2020-01-01" in drift:ignore marker, want key="value" attributes`,
+		},
+		{
+			name:    "stray text",
+			content: "<!-- drift:ignore reason=\"flaky\" for now -->\nText.",
+			wantErr: `README.md:1: unexpected "for now" in drift:ignore marker, want key="value" attributes`,
+		},
+		{
+			name:    "unterminated region",
+			content: "Text.\n<!-- drift:ignore-start -->\nText.",
+			wantErr: "README.md:2: drift:ignore-start without a drift:ignore-end",
+		},
+		{
+			name:    "stray end",
+			content: "Text.\n<!-- drift:ignore-end -->",
+			wantErr: "README.md:2: drift:ignore-end without a drift:ignore-start",
+		},
+		{
+			name:    "nested region",
+			content: "<!-- drift:ignore-start -->\n<!-- drift:ignore-start -->\n<!-- drift:ignore-end -->",
+			wantErr: "README.md:2: drift:ignore-start inside the drift:ignore-start at line 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := files.StripSuppressions("README.md", tt.content, today)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("StripSuppressions() error = %v, want %q", err, tt.wantErr)
+			}
+			// Every error is a problem with the markers, not a transient one.
+			var marker *files.MarkerError
+			var expired *files.ExpiredSuppressionError
+			if !errors.As(err, &marker) && !errors.As(err, &expired) {
+				t.Errorf("StripSuppressions() error = %T, want a *MarkerError or *ExpiredSuppressionError", err)
+			}
+		})
+	}
+}
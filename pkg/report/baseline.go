@@ -0,0 +1,171 @@
+package report
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+)
+
+// DefaultBaselinePath is where drift baseline writes the baseline by default.
+const DefaultBaselinePath = ".drift-baseline.json"
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// Baseline is a list of known drift that doesn't fail drift check.
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+// BaselineEntry is a known finding of a rule, or known drift of a rule
+// without findings.
+type BaselineEntry struct {
+	Rule        string `json:"rule"`
+	Fingerprint string `json:"fingerprint"`
+	// Description is the finding's description or the rule's reason, to
+	// make the baseline reviewable. It is not used for matching.
+	Description string `json:"description,omitempty"`
+}
+
+// Fingerprint identifies a finding across checks. Models word their findings
+// and estimate lines differently from one run to the next, so only the
+// category and the files and symbol concerned are used. Drift without
+// findings is fingerprinted as a zero Finding.
+func Fingerprint(f assessor.Finding) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{f.Category, f.DocFile, f.CodeFile, f.Symbol}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewBaseline records the drift of every out-of-sync rule in the report,
+// including low-confidence drift.
+func NewBaseline(r *Report) *Baseline {
+	b := &Baseline{Version: baselineVersion, Entries: []BaselineEntry{}}
+	seen := make(map[BaselineEntry]bool)
+	add := func(rule string, f assessor.Finding, description string) {
+		key := BaselineEntry{Rule: rule, Fingerprint: Fingerprint(f)}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		key.Description = description
+		b.Entries = append(b.Entries, key)
+	}
+
+	for _, rule := range r.Rules {
+		if rule.Status != StatusOutOfSync && rule.Status != StatusWarning {
+			continue
+		}
+		if len(rule.Result.Findings) == 0 {
+			add(rule.Name, assessor.Finding{}, rule.Result.Reason)
+		}
+		for _, f := range rule.Result.Findings {
+			add(rule.Name, f, f.Description)
+		}
+	}
+
+	sort.Slice(b.Entries, func(i, j int) bool {
+		if b.Entries[i].Rule != b.Entries[j].Rule {
+			return b.Entries[i].Rule < b.Entries[j].Rule
+		}
+		return b.Entries[i].Fingerprint < b.Entries[j].Fingerprint
+	})
+	return b
+}
+
+// LoadBaseline reads a baseline written by Baseline.Write.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", b.Version, path)
+	}
+	return &b, nil
+}
+
+// Write saves the baseline to path.
+func (b *Baseline) Write(path string) error {
+	// The baseline is meant to be committed and reviewed, so keep it
+	// readable.
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data.Bytes(), 0644)
+}
+
+// ApplyBaseline accepts the drift recorded in b. Findings in the baseline are
+// moved from the rules' results to their Baselined findings, and rules left
+// without new drift become StatusBaselined. The entries of checked rules that
+// are no longer out of sync are listed in FixedBaseline, so that they can be
+// pruned.
+func (r *Report) ApplyBaseline(b *Baseline) {
+	known := make(map[BaselineEntry]bool, len(b.Entries))
+	for _, e := range b.Entries {
+		known[BaselineEntry{Rule: e.Rule, Fingerprint: e.Fingerprint}] = true
+	}
+	seen := make(map[BaselineEntry]bool)
+	isKnown := func(rule string, f assessor.Finding) bool {
+		key := BaselineEntry{Rule: rule, Fingerprint: Fingerprint(f)}
+		seen[key] = true
+		return known[key]
+	}
+
+	checked := make(map[string]bool, len(r.Rules))
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.Status != StatusError {
+			checked[rule.Name] = true
+		}
+		if rule.Status != StatusOutOfSync && rule.Status != StatusWarning {
+			continue
+		}
+
+		if len(rule.Result.Findings) == 0 {
+			if isKnown(rule.Name, assessor.Finding{}) {
+				rule.Status = StatusBaselined
+			}
+			continue
+		}
+		var fresh []assessor.Finding
+		for _, f := range rule.Result.Findings {
+			if isKnown(rule.Name, f) {
+				rule.Baselined = append(rule.Baselined, f)
+			} else {
+				fresh = append(fresh, f)
+			}
+		}
+		if len(rule.Baselined) == 0 {
+			continue
+		}
+		result := *rule.Result
+		result.Findings = fresh
+		rule.Result = &result
+		if len(fresh) == 0 {
+			rule.Status = StatusBaselined
+		}
+	}
+
+	for _, e := range b.Entries {
+		key := BaselineEntry{Rule: e.Rule, Fingerprint: e.Fingerprint}
+		if checked[e.Rule] && !seen[key] {
+			r.FixedBaseline = append(r.FixedBaseline, e)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package report_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	tokenFinding = assessor.Finding{DocFile: "docs/auth.md", Line: 3, CodeFile: "src/auth/login.go", Symbol: "Login", Category: assessor.CategoryMissingParam, Description: "token is not documented"}
+	scopeFinding = assessor.Finding{DocFile: "docs/auth.md", Line: 9, CodeFile: "src/auth/login.go", Symbol: "Scope", Category: assessor.CategoryWrongType, Description: "scope is a list"}
+)
+
+// driftReport returns a report with an in-sync rule, a rule with findings
+// and a rule without findings.
+func driftReport(authFindings ...assessor.Finding) *report.Report {
+	return &report.Report{Rules: []report.RuleResult{
+		{Name: "Users", Status: report.StatusInSync, Result: &assessor.AssessmentResult{IsInSync: true}},
+		{Name: "Auth", Status: report.StatusOutOfSync, Result: &assessor.AssessmentResult{Reason: "auth changed", Findings: authFindings}},
+		{Name: "Readme", Status: report.StatusOutOfSync, Result: &assessor.AssessmentResult{Reason: "the install command changed"}},
+	}}
+}
+
+func TestFingerprint(t *testing.T) {
+	reworded := tokenFinding
+	reworded.Line = 4
+	reworded.Description = "the token parameter is missing"
+	reworded.Suggestion = "Document token."
+	assert.Equal(t, report.Fingerprint(tokenFinding), report.Fingerprint(reworded))
+	assert.NotEqual(t, report.Fingerprint(tokenFinding), report.Fingerprint(scopeFinding))
+}
+
+func TestNewBaseline(t *testing.T) {
+	b := report.NewBaseline(driftReport(tokenFinding, scopeFinding, tokenFinding))
+
+	assert.Equal(t, 1, b.Version)
+	require.Len(t, b.Entries, 3)
+	assert.Equal(t, "Auth", b.Entries[0].Rule)
+	assert.Equal(t, "Auth", b.Entries[1].Rule)
+	assert.ElementsMatch(t,
+		[]string{report.Fingerprint(tokenFinding), report.Fingerprint(scopeFinding)},
+		[]string{b.Entries[0].Fingerprint, b.Entries[1].Fingerprint})
+	assert.Equal(t, report.BaselineEntry{
+		Rule:        "Readme",
+		Fingerprint: report.Fingerprint(assessor.Finding{}),
+		Description: "the install command changed",
+	}, b.Entries[2])
+}
+
+func TestBaseline_WriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	want := report.NewBaseline(driftReport(tokenFinding))
+	require.NoError(t, want.Write(path))
+
+	got, err := report.LoadBaseline(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	require.NoError(t, (&report.Baseline{Version: 2}).Write(path))
+	_, err = report.LoadBaseline(path)
+	assert.ErrorContains(t, err, "unsupported baseline version 2")
+}
+
+func TestApplyBaseline(t *testing.T) {
+	baseline := report.NewBaseline(driftReport(tokenFinding))
+
+	t.Run("known drift", func(t *testing.T) {
+		rep := driftReport(tokenFinding)
+		rep.ApplyBaseline(baseline)
+
+		assert.Equal(t, report.StatusBaselined, rep.Rules[1].Status)
+		assert.Empty(t, rep.Rules[1].Result.Findings)
+		assert.Equal(t, []assessor.Finding{tokenFinding}, rep.Rules[1].Baselined)
+		assert.Equal(t, report.StatusBaselined, rep.Rules[2].Status)
+		assert.Empty(t, rep.FixedBaseline)
+		assert.False(t, rep.HasFailingDrift())
+	})
+
+	t.Run("new finding", func(t *testing.T) {
+		rep := driftReport(tokenFinding, scopeFinding)
+		rep.ApplyBaseline(baseline)
+
+		assert.Equal(t, report.StatusOutOfSync, rep.Rules[1].Status)
+		assert.Equal(t, []assessor.Finding{scopeFinding}, rep.Rules[1].Result.Findings)
+		assert.Equal(t, []assessor.Finding{tokenFinding}, rep.Rules[1].Baselined)
+		assert.True(t, rep.HasFailingDrift())
+	})
+
+	t.Run("fixed drift", func(t *testing.T) {
+		rep := driftReport(scopeFinding)
+		rep.Rules[2] = report.RuleResult{Name: "Readme", Status: report.StatusInSync, Result: &assessor.AssessmentResult{IsInSync: true}}
+		rep.ApplyBaseline(baseline)
+
+		assert.Equal(t, report.StatusOutOfSync, rep.Rules[1].Status)
+		require.Len(t, rep.FixedBaseline, 2)
+		assert.Equal(t, "token is not documented", rep.FixedBaseline[0].Description)
+		assert.Equal(t, "Readme", rep.FixedBaseline[1].Rule)
+	})
+
+	t.Run("unchecked rules are not fixed", func(t *testing.T) {
+		rep := driftReport()
+		rep.Rules = rep.Rules[:1]
+		rep.Rules = append(rep.Rules, report.RuleResult{Name: "Auth", Status: report.StatusError, Error: "timeout"})
+		rep.ApplyBaseline(baseline)
+
+		assert.Empty(t, rep.FixedBaseline)
+	})
+}
+
+func TestWriteText_Baseline(t *testing.T) {
+	rep := driftReport(tokenFinding, scopeFinding)
+	rep.ConfigPath = ".drift.yaml"
+	rep.Provider = "gemini"
+	rep.TotalRules = 3
+	rep.BaselinePath = ".drift-baseline.json"
+	rep.Rules[2] = report.RuleResult{Name: "Readme", Status: report.StatusInSync, Result: &assessor.AssessmentResult{IsInSync: true}}
+	rep.ApplyBaseline(&report.Baseline{Version: 1, Entries: []report.BaselineEntry{
+		{Rule: "Auth", Fingerprint: report.Fingerprint(tokenFinding), Description: "token is not documented"},
+		{Rule: "Readme", Fingerprint: report.Fingerprint(assessor.Finding{}), Description: "the install command changed"},
+	}})
+
+	for i := range rep.Rules {
+		rep.Rules[i].Provider = "gemini"
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf, rep))
+
+	want := `Loaded 3 rules from .drift.yaml (provider: gemini)
+  - Rule: Users
+    Result: In Sync
+  - Rule: Auth
+    Result: Out of Sync (auth changed)
+      - [wrong_type] scope is a list
+        Docs: docs/auth.md:9
+        Code: src/auth/login.go Scope
+    1 findings are accepted by the baseline.
+  - Rule: Readme
+    Result: In Sync
+1 baseline entries are fixed and can be pruned from .drift-baseline.json:
+  - Readme: the install command changed
+Drift detected.
+`
+	assert.Equal(t, want, buf.String())
+}
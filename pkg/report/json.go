@@ -0,0 +1,19 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonReport adds fields derived from the report to its JSON form.
+type jsonReport struct {
+	*Report
+	InSync bool `json:"in_sync"`
+}
+
+// WriteJSON renders the report as a single indented JSON document.
+func WriteJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonReport{Report: r, InSync: r.InSync()})
+}
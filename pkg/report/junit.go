@@ -0,0 +1,161 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitProblem    `xml:"failure,omitempty"`
+	Error      *junitProblem    `xml:"error,omitempty"`
+	Skipped    *junitSkipped    `xml:"skipped,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit renders the report as a JUnit XML document with one test case
+// per rule: passed when in sync, failed when out of sync, errored when the
+// rule could not be checked and skipped when it wasn't triggered. Rules whose
+// drift has an effective severity below the report's FailOn, lowered to a
+// warning for drift with a low confidence, pass with the drift in their
+// output. The severity of each rule is recorded as a test case property.
+func WriteJUnit(w io.Writer, r *Report) error {
+	suite := junitTestSuite{
+		Name: r.ConfigPath,
+		Time: junitSeconds(r.Duration),
+	}
+	if !r.StartedAt.IsZero() {
+		suite.Timestamp = r.StartedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	for _, rule := range r.Rules {
+		tc := junitTestCase{Name: rule.Name, ClassName: toolName, Time: junitSeconds(rule.Duration)}
+		if rule.Severity != "" {
+			tc.Properties = &junitProperties{Properties: []junitProperty{{Name: "severity", Value: rule.Severity}}}
+		}
+		switch rule.Status {
+		case StatusOutOfSync:
+			if !r.Failing(rule) {
+				tc.SystemOut = fmt.Sprintf("Out of sync, with a severity of %s: %s\n%s", rule.Severity, rule.Result.Reason, junitFindings(rule))
+				break
+			}
+			suite.Failures++
+			tc.Failure = &junitProblem{
+				Message: rule.Result.Reason,
+				Type:    string(rule.Status),
+				Text:    junitFindings(rule),
+			}
+		case StatusBaselined:
+			tc.SystemOut = fmt.Sprintf("Out of sync, accepted by the baseline: %s\n", rule.Result.Reason)
+		case StatusWarning:
+			if !r.Failing(rule) {
+				tc.SystemOut = fmt.Sprintf("Possibly out of sync, with a confidence of %.2f: %s\n%s", *rule.Result.Confidence, rule.Result.Reason, junitFindings(rule))
+				break
+			}
+			suite.Failures++
+			tc.Failure = &junitProblem{
+				Message: rule.Result.Reason,
+				Type:    string(rule.Status),
+				Text:    junitFindings(rule),
+			}
+		case StatusError:
+			suite.Errors++
+			tc.Error = &junitProblem{Message: rule.Error, Type: string(rule.Status)}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, name := range r.SkippedRules {
+		suite.Skipped++
+		suite.Cases = append(suite.Cases, junitTestCase{
+			Name:      name,
+			ClassName: toolName,
+			Time:      junitSeconds(0),
+			Skipped:   &junitSkipped{Message: "not triggered by the changed files"},
+		})
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{
+		Name:     toolName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitFindings lists the findings of a rule as the body of its failure.
+func junitFindings(rule RuleResult) string {
+	var b strings.Builder
+	for _, f := range rule.Result.Findings {
+		b.WriteString(findingMessage(f))
+		if location := findingLocation(f); location != "" {
+			fmt.Fprintf(&b, " at %s", location)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,160 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, sampleReport(), report.FormatJUnit))
+	require.True(t, strings.HasPrefix(buf.String(), xml.Header), "missing XML header")
+
+	type problem struct {
+		Message string `xml:"message,attr"`
+		Text    string `xml:",chardata"`
+	}
+	var suites struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name      string `xml:"name,attr"`
+			Timestamp string `xml:"timestamp,attr"`
+			Cases     []struct {
+				Name    string   `xml:"name,attr"`
+				Time    string   `xml:"time,attr"`
+				Failure *problem `xml:"failure"`
+				Error   *problem `xml:"error"`
+				Skipped *problem `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 4, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Errors)
+	assert.Equal(t, 1, suites.Skipped)
+
+	require.Len(t, suites.Suites, 1)
+	suite := suites.Suites[0]
+	assert.Equal(t, ".drift.yaml", suite.Name)
+	assert.Equal(t, "2025-01-02T03:04:05", suite.Timestamp)
+	require.Len(t, suite.Cases, 4)
+
+	passed, failed, errored, skipped := suite.Cases[0], suite.Cases[1], suite.Cases[2], suite.Cases[3]
+	assert.Equal(t, "Users", passed.Name)
+	assert.Nil(t, passed.Failure)
+	assert.Nil(t, passed.Error)
+	assert.Nil(t, passed.Skipped)
+
+	assert.Equal(t, "Auth", failed.Name)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, "token is undocumented", failed.Failure.Message)
+	assert.Contains(t, failed.Failure.Text, "[missing_param] token is not documented")
+	assert.Contains(t, failed.Failure.Text, "docs/auth.md:3 (Login)")
+
+	assert.Equal(t, "Broken", errored.Name)
+	require.NotNil(t, errored.Error)
+	assert.Contains(t, errored.Error.Message, "syntax error")
+
+	assert.Equal(t, "Skipped", skipped.Name)
+	require.NotNil(t, skipped.Skipped)
+}
+
+func TestWriteJUnit_Warning(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf, warningReport()))
+
+	var suites struct {
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Failure   *struct{} `xml:"failure"`
+				SystemOut string    `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 0, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+	require.Len(t, suites.Suites[0].Cases, 1)
+	tc := suites.Suites[0].Cases[0]
+	assert.Nil(t, tc.Failure)
+	assert.Contains(t, tc.SystemOut, "confidence of 0.40: the wording differs")
+	assert.Contains(t, tc.SystemOut, "[other] the wording differs at docs/style.md:5")
+}
+
+func TestWriteJUnit_WarningFailOn(t *testing.T) {
+	rep := warningReport()
+	rep.FailOn = config.SeverityWarning
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf, rep))
+
+	var suites struct {
+		Failures int `xml:"failures,attr"`
+		Suites   []struct {
+			Cases []struct {
+				Failure *struct {
+					Type string `xml:"type,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 1, suites.Failures)
+	require.Len(t, suites.Suites, 1)
+	require.Len(t, suites.Suites[0].Cases, 1)
+	require.NotNil(t, suites.Suites[0].Cases[0].Failure)
+	assert.Equal(t, "warning", suites.Suites[0].Cases[0].Failure.Type)
+}
+
+func TestWriteJUnit_FailOn(t *testing.T) {
+	rep := sampleReport()
+	rep.Rules[1].Severity = config.SeverityWarning
+
+	type property struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	}
+	decode := func(rep *report.Report) (failures int, failure *struct{}, properties []property, systemOut string) {
+		var buf bytes.Buffer
+		require.NoError(t, report.WriteJUnit(&buf, rep))
+		var suites struct {
+			Failures int `xml:"failures,attr"`
+			Suites   []struct {
+				Cases []struct {
+					Properties []property `xml:"properties>property"`
+					Failure    *struct{}  `xml:"failure"`
+					SystemOut  string     `xml:"system-out"`
+				} `xml:"testcase"`
+			} `xml:"testsuite"`
+		}
+		require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+		tc := suites.Suites[0].Cases[1]
+		return suites.Failures, tc.Failure, tc.Properties, tc.SystemOut
+	}
+
+	failures, failure, properties, systemOut := decode(rep)
+	assert.Equal(t, 0, failures)
+	assert.Nil(t, failure)
+	assert.Equal(t, []property{{Name: "severity", Value: "warning"}}, properties)
+	assert.Contains(t, systemOut, "Out of sync, with a severity of warning: token is undocumented")
+
+	rep.FailOn = config.SeverityWarning
+	failures, failure, _, _ = decode(rep)
+	assert.Equal(t, 1, failures)
+	assert.NotNil(t, failure)
+}
@@ -0,0 +1,204 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/files"
+)
+
+// Output formats supported by Write.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+	FormatJUnit = "junit"
+)
+
+// Formats lists every supported output format.
+var Formats = []string{FormatText, FormatJSON, FormatSARIF, FormatJUnit}
+
+// Status is the outcome of checking a single rule.
+type Status string
+
+const (
+	StatusInSync    Status = "in_sync"
+	StatusOutOfSync Status = "out_of_sync"
+	// StatusWarning means the rule is out of sync, but the assessment's
+	// confidence is below the rule's minimum, so it doesn't fail the check.
+	StatusWarning Status = "warning"
+	// StatusBaselined means the rule is out of sync, but all of its drift
+	// is accepted by the baseline.
+	StatusBaselined Status = "baselined"
+	StatusError     Status = "error"
+)
+
+// Report is the outcome of a drift check.
+type Report struct {
+	ToolVersion  string       `json:"tool_version,omitempty"`
+	ConfigPath   string       `json:"config_path"`
+	Provider     string       `json:"provider"`
+	Model        string       `json:"model,omitempty"`
+	TotalRules   int          `json:"total_rules"`
+	ChangedFiles []string     `json:"changed_files,omitempty"`
+	Rules        []RuleResult `json:"rules"`
+	// SkippedRules are the names of rules not triggered by ChangedFiles.
+	SkippedRules []string `json:"skipped_rules"`
+	// RuleNames are the names of every configured rule, in the order of
+	// the configuration, so that SARIF rule IDs don't depend on which rules
+	// were triggered. When empty, Rules and SkippedRules are used instead.
+	RuleNames []string `json:"-"`
+	// FailOn is the minimum severity of drift that fails the check. Empty
+	// means config.SeverityError.
+	FailOn string `json:"fail_on,omitempty"`
+	// BaselinePath is the baseline applied to the report, if any.
+	BaselinePath string `json:"baseline_path,omitempty"`
+	// FixedBaseline are the baseline entries of checked rules that are no
+	// longer out of sync.
+	FixedBaseline []BaselineEntry `json:"fixed_baseline,omitempty"`
+	StartedAt     time.Time       `json:"started_at"`
+	Duration      time.Duration   `json:"duration_ns"`
+}
+
+// RuleResult is the outcome of checking a single rule.
+type RuleResult struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+	// CodeFiles and DocFiles are nil when the check failed before the
+	// files were read.
+	CodeFiles *FileStats `json:"code_files,omitempty"`
+	DocFiles  *FileStats `json:"doc_files,omitempty"`
+	// Suppressions are the regions of the docs hidden from the assessor
+	// by drift:ignore markers.
+	Suppressions []files.Suppression `json:"suppressions,omitempty"`
+	Status       Status              `json:"status"`
+	// Severity is the severity of the rule's drift, one of
+	// config.Severities.
+	Severity string                     `json:"severity,omitempty"`
+	Result   *assessor.AssessmentResult `json:"result,omitempty"`
+	Error    string                     `json:"error,omitempty"`
+	// Invalid means the rule's error is a problem in the repository, such
+	// as a malformed or expired drift:ignore suppression, rather than a
+	// failure to reach the provider. It fails the check even when errors
+	// don't.
+	Invalid  bool          `json:"invalid,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+	// MinConfidence is the confidence below which the rule's drift is only
+	// a warning, or 0 if drift always fails the check.
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	// Baselined are the findings accepted by the baseline, which are not
+	// in Result.
+	Baselined []assessor.Finding `json:"baselined,omitempty"`
+}
+
+// FileStats summarises the files matched by a rule's globs.
+type FileStats struct {
+	Count int      `json:"count"`
+	Bytes int      `json:"bytes"`
+	Paths []string `json:"paths,omitempty"`
+}
+
+// InSync reports whether every checked rule is in sync.
+func (r *Report) InSync() bool {
+	for _, rule := range r.Rules {
+		if rule.Status != StatusInSync {
+			return false
+		}
+	}
+	return true
+}
+
+// HasDrift reports whether any checked rule is out of sync.
+func (r *Report) HasDrift() bool {
+	return r.count(StatusOutOfSync) > 0
+}
+
+// EffectiveSeverity returns the severity of a rule's drift, lowered to
+// config.SeverityWarning if the drift has a low confidence.
+func (rule RuleResult) EffectiveSeverity() string {
+	if rule.Status == StatusWarning && config.SeverityAtLeast(rule.Severity, config.SeverityError) {
+		return config.SeverityWarning
+	}
+	return rule.Severity
+}
+
+// Failing reports whether the drift of a rule fails the check, i.e. the rule
+// is out of sync, or possibly out of sync, with an effective severity of at
+// least FailOn.
+func (r *Report) Failing(rule RuleResult) bool {
+	if rule.Status != StatusOutOfSync && rule.Status != StatusWarning {
+		return false
+	}
+	return config.SeverityAtLeast(rule.EffectiveSeverity(), r.failOn())
+}
+
+// failOn returns the minimum severity of drift that fails the check.
+func (r *Report) failOn() string {
+	if r.FailOn == "" {
+		return config.SeverityError
+	}
+	return r.FailOn
+}
+
+// HasFailingDrift reports whether the drift of any checked rule fails the
+// check.
+func (r *Report) HasFailingDrift() bool {
+	for _, rule := range r.Rules {
+		if r.Failing(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Warnings returns the number of rules whose drift was reported as a warning.
+func (r *Report) Warnings() int {
+	return r.count(StatusWarning)
+}
+
+// Invalid returns the number of rules that could not be checked because of a
+// problem in the repository.
+func (r *Report) Invalid() int {
+	n := 0
+	for _, rule := range r.Rules {
+		if rule.Status == StatusError && rule.Invalid {
+			n++
+		}
+	}
+	return n
+}
+
+// Errors returns the number of rules that could not be checked.
+func (r *Report) Errors() int {
+	return r.count(StatusError)
+}
+
+func (r *Report) count(status Status) int {
+	n := 0
+	for _, rule := range r.Rules {
+		if rule.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Write renders the report to w in the given format.
+func Write(w io.Writer, r *Report, format string) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, r)
+	case FormatJSON:
+		return WriteJSON(w, r)
+	case FormatSARIF:
+		return WriteSARIF(w, r)
+	case FormatJUnit:
+		return WriteJUnit(w, r)
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
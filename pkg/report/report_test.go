@@ -0,0 +1,280 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/files"
+	"github.com/driftee-ai/drift/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleReport returns a report with one rule of each status.
+func sampleReport() *report.Report {
+	return &report.Report{
+		ConfigPath:   ".drift.yaml",
+		Provider:     "gemini",
+		TotalRules:   4,
+		ChangedFiles: []string{"src/api/user.go"},
+		SkippedRules: []string{"Skipped"},
+		StartedAt:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Duration:     2 * time.Second,
+		Rules: []report.RuleResult{
+			{
+				Name:      "Users",
+				Provider:  "gemini",
+				CodeFiles: &report.FileStats{Count: 1, Bytes: 10},
+				DocFiles:  &report.FileStats{Count: 1, Bytes: 20},
+				Status:    report.StatusInSync,
+				Result:    &assessor.AssessmentResult{IsInSync: true},
+			},
+			{
+				Name:      "Auth",
+				Provider:  "openai",
+				Model:     "gpt-4o",
+				CodeFiles: &report.FileStats{Count: 2, Bytes: 30},
+				DocFiles:  &report.FileStats{Count: 1, Bytes: 40},
+				Status:    report.StatusOutOfSync,
+				Severity:  config.SeverityError,
+				Result: &assessor.AssessmentResult{
+					IsInSync: false,
+					Reason:   "token is undocumented",
+					Findings: []assessor.Finding{{
+						DocFile:     "docs/auth.md",
+						Line:        3,
+						Section:     "Login",
+						CodeFile:    "src/auth/login.go",
+						Symbol:      "Login",
+						Category:    assessor.CategoryMissingParam,
+						Description: "token is not documented",
+						Suggestion:  "Document token.",
+					}},
+				},
+			},
+			{
+				Name:     "Broken",
+				Provider: "gemini",
+				Status:   report.StatusError,
+				Error:    "failed to find code files: syntax error in pattern",
+			},
+		},
+	}
+}
+
+// warningReport returns a report with a single rule of low-confidence drift.
+func warningReport() *report.Report {
+	confidence := 0.4
+	return &report.Report{
+		ConfigPath: ".drift.yaml",
+		Provider:   "gemini",
+		TotalRules: 1,
+		Rules: []report.RuleResult{{
+			Name:          "Style",
+			Provider:      "gemini",
+			DocFiles:      &report.FileStats{Count: 1, Bytes: 20, Paths: []string{"docs/style.md"}},
+			Status:        report.StatusWarning,
+			MinConfidence: 0.7,
+			Result: &assessor.AssessmentResult{
+				Reason:     "the wording differs",
+				Confidence: &confidence,
+				Findings: []assessor.Finding{{
+					DocFile:     "docs/style.md",
+					Line:        5,
+					Category:    assessor.CategoryOther,
+					Description: "the wording differs",
+				}},
+			},
+		}},
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, sampleReport(), report.FormatText))
+
+	want := `Loaded 4 rules from .drift.yaml (provider: gemini)
+Filtering rules based on 1 changed files. 3 rules were triggered.
+  - Rule: Users
+    Found 1 code files, total size: 10 bytes
+    Found 1 doc files, total size: 20 bytes
+    Result: In Sync
+  - Rule: Auth
+    Provider: openai (gpt-4o)
+    Found 2 code files, total size: 30 bytes
+    Found 1 doc files, total size: 40 bytes
+    Result: Out of Sync (token is undocumented)
+    Severity: error
+      - [missing_param] token is not documented
+        Docs: docs/auth.md:3 (Login)
+        Code: src/auth/login.go Login
+        Suggestion: Document token.
+  - Rule: Broken
+    Error: failed to find code files: syntax error in pattern
+1 rules could not be checked.
+Drift detected.
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteText_BelowFailOn(t *testing.T) {
+	rep := sampleReport()
+	rep.Rules[1].Severity = config.SeverityWarning
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, rep, report.FormatText))
+	assert.NotContains(t, buf.String(), "Drift detected.")
+	assert.Contains(t, buf.String(), "Drift detected below --fail-on error, which doesn't fail the check.\n")
+
+	rep.FailOn = config.SeverityWarning
+	buf.Reset()
+	require.NoError(t, report.Write(&buf, rep, report.FormatText))
+	assert.True(t, strings.HasSuffix(buf.String(), "Drift detected.\n"))
+}
+
+func TestWriteText_Warning(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, warningReport(), report.FormatText))
+
+	want := `Loaded 1 rules from .drift.yaml (provider: gemini)
+  - Rule: Style
+    Found 1 doc files, total size: 20 bytes
+    Warning: Possibly Out of Sync (the wording differs)
+    Confidence 0.40 is below the minimum of 0.70.
+    Severity: warning
+      - [other] the wording differs
+        Docs: docs/style.md:5
+1 rules have low-confidence drift.
+`
+	assert.Equal(t, want, buf.String())
+	assert.False(t, warningReport().HasDrift())
+}
+
+func TestWriteText_Dissent(t *testing.T) {
+	rep := &report.Report{
+		ConfigPath: ".drift.yaml",
+		Provider:   "consensus",
+		TotalRules: 1,
+		Rules: []report.RuleResult{{
+			Name:     "Users",
+			Provider: "consensus",
+			Status:   report.StatusInSync,
+			Result: &assessor.AssessmentResult{
+				IsInSync: true,
+				Dissent: []assessor.Dissent{
+					{Voter: "openai (gpt-4o)", Reason: "age is undocumented"},
+					{Voter: "anthropic", Error: "anthropic API error: overloaded"},
+				},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, rep, report.FormatText))
+
+	want := `Loaded 1 rules from .drift.yaml (provider: consensus)
+  - Rule: Users
+    Result: In Sync
+    Dissent: openai (gpt-4o) found it out of sync (age is undocumented)
+    Dissent: anthropic failed: anthropic API error: overloaded
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestEffectiveSeverity(t *testing.T) {
+	tests := []struct {
+		rule report.RuleResult
+		want string
+	}{
+		{rule: report.RuleResult{Status: report.StatusOutOfSync, Severity: config.SeverityError}, want: config.SeverityError},
+		{rule: report.RuleResult{Status: report.StatusWarning, Severity: config.SeverityError}, want: config.SeverityWarning},
+		{rule: report.RuleResult{Status: report.StatusWarning}, want: config.SeverityWarning},
+		{rule: report.RuleResult{Status: report.StatusWarning, Severity: config.SeverityInfo}, want: config.SeverityInfo},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.rule.EffectiveSeverity(), "%s %q", tt.rule.Status, tt.rule.Severity)
+	}
+}
+
+func TestWriteText_Suppressions(t *testing.T) {
+	rep := &report.Report{
+		ConfigPath: ".drift.yaml",
+		Provider:   "gemini",
+		TotalRules: 1,
+		Rules: []report.RuleResult{{
+			Name:     "Users",
+			Provider: "gemini",
+			DocFiles: &report.FileStats{Count: 1, Bytes: 20},
+			Suppressions: []files.Suppression{
+				{File: "docs/users.md", Line: 3, Lines: 4, Reason: "planned"},
+				{File: "docs/users.md", Line: 12, Lines: 2},
+			},
+			Status: report.StatusInSync,
+			Result: &assessor.AssessmentResult{IsInSync: true},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, rep, report.FormatText))
+
+	want := `Loaded 1 rules from .drift.yaml (provider: gemini)
+  - Rule: Users
+    Found 1 doc files, total size: 20 bytes
+    Ignored 2 regions of the docs marked with drift:ignore
+    Result: In Sync
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, sampleReport(), report.FormatJSON))
+
+	var got struct {
+		ConfigPath   string   `json:"config_path"`
+		Provider     string   `json:"provider"`
+		InSync       bool     `json:"in_sync"`
+		SkippedRules []string `json:"skipped_rules"`
+		Rules        []struct {
+			Name      string            `json:"name"`
+			Status    string            `json:"status"`
+			Severity  string            `json:"severity"`
+			Error     string            `json:"error"`
+			CodeFiles *report.FileStats `json:"code_files"`
+			Result    *struct {
+				Reason   string `json:"reason"`
+				Findings []struct {
+					Category string `json:"category"`
+				} `json:"findings"`
+			} `json:"result"`
+		} `json:"rules"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, ".drift.yaml", got.ConfigPath)
+	assert.Equal(t, "gemini", got.Provider)
+	assert.False(t, got.InSync)
+	assert.Equal(t, []string{"Skipped"}, got.SkippedRules)
+	require.Len(t, got.Rules, 3)
+	assert.Equal(t, "in_sync", got.Rules[0].Status)
+	assert.Equal(t, &report.FileStats{Count: 1, Bytes: 10}, got.Rules[0].CodeFiles)
+	assert.Equal(t, "out_of_sync", got.Rules[1].Status)
+	assert.Equal(t, "error", got.Rules[1].Severity)
+	require.NotNil(t, got.Rules[1].Result)
+	assert.Equal(t, "token is undocumented", got.Rules[1].Result.Reason)
+	require.Len(t, got.Rules[1].Result.Findings, 1)
+	assert.Equal(t, "missing_param", got.Rules[1].Result.Findings[0].Category)
+	assert.Equal(t, "error", got.Rules[2].Status)
+	assert.Nil(t, got.Rules[2].CodeFiles)
+	assert.Contains(t, got.Rules[2].Error, "syntax error")
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, report.Write(&buf, sampleReport(), "xml"))
+}
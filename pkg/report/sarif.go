@@ -0,0 +1,313 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "drift"
+	toolURI      = "https://github.com/driftee-ai/drift"
+)
+
+// The types below cover the subset of SARIF 2.1.0 that drift emits.
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string            `json:"name"`
+	Version        string            `json:"version,omitempty"`
+	InformationURI string            `json:"informationUri"`
+	Rules          []sarifDescriptor `json:"rules"`
+}
+
+type sarifDescriptor struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name"`
+	ShortDescription     sarifMessage        `json:"shortDescription"`
+	DefaultConfiguration *sarifConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level          string              `json:"level"`
+	Message        sarifMessage        `json:"message"`
+	AssociatedRule *sarifRuleReference `json:"associatedRule,omitempty"`
+}
+
+type sarifRuleReference struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+}
+
+type sarifResult struct {
+	RuleID        string          `json:"ruleId"`
+	RuleIndex     int             `json:"ruleIndex"`
+	Level         string          `json:"level"`
+	Message       sarifMessage    `json:"message"`
+	Locations     []sarifLocation `json:"locations,omitempty"`
+	BaselineState string          `json:"baselineState,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSARIF renders the report as a SARIF 2.1.0 log, so that drift shows up
+// as code-scanning alerts. Each out-of-sync rule produces one result per
+// finding, or a single result pointing at the rule's doc files when the
+// provider reported no findings. The level of the results follows the rule's
+// severity, and is at most a warning for low-confidence drift. When a
+// baseline was applied, results are marked as new or as unchanged from the
+// baseline. Rules that failed to run are reported as tool execution
+// notifications.
+func WriteSARIF(w io.Writer, r *Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           toolName,
+			Version:        r.ToolVersion,
+			InformationURI: toolURI,
+			Rules:          []sarifDescriptor{},
+		}},
+		Results: []sarifResult{},
+	}
+	invocation := sarifInvocation{ExecutionSuccessful: true}
+
+	ids := sarifRuleIDs(r)
+	for i, rule := range r.Rules {
+		id := ids[i]
+		descriptor := sarifDescriptor{
+			ID:               id,
+			Name:             rule.Name,
+			ShortDescription: sarifMessage{Text: fmt.Sprintf("Documentation for %q is in sync with the code", rule.Name)},
+		}
+		if rule.Severity != "" {
+			descriptor.DefaultConfiguration = &sarifConfiguration{Level: sarifSeverityLevel(rule.Severity)}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, descriptor)
+
+		switch rule.Status {
+		case StatusOutOfSync, StatusWarning, StatusBaselined:
+			run.Results = append(run.Results, sarifRuleResults(r, id, i, rule)...)
+		case StatusError:
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:          "error",
+				Message:        sarifMessage{Text: rule.Error},
+				AssociatedRule: &sarifRuleReference{ID: id, Index: i},
+			})
+		}
+	}
+	run.Invocations = []sarifInvocation{invocation}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifSeverityLevel maps the severity of drift to a SARIF level.
+func sarifSeverityLevel(severity string) string {
+	switch severity {
+	case config.SeverityWarning:
+		return "warning"
+	case config.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
+
+// sarifLevel returns the SARIF level of a rule's drift: the level of its
+// effective severity, which is lowered to a warning if the drift has a low
+// confidence.
+func sarifLevel(rule RuleResult) string {
+	return sarifSeverityLevel(rule.EffectiveSeverity())
+}
+
+// sarifRuleResults converts an out-of-sync rule into SARIF results, for both
+// its new findings and those accepted by the report's baseline.
+func sarifRuleResults(r *Report, id string, index int, rule RuleResult) []sarifResult {
+	level := sarifLevel(rule)
+	newState, knownState := "", ""
+	if r.BaselinePath != "" {
+		newState, knownState = "new", "unchanged"
+	}
+
+	if rule.Status == StatusBaselined && len(rule.Baselined) == 0 {
+		return sarifResults(id, index, rule, level, nil, knownState)
+	}
+	var results []sarifResult
+	if rule.Status != StatusBaselined {
+		results = sarifResults(id, index, rule, level, rule.Result.Findings, newState)
+	}
+	if len(rule.Baselined) > 0 {
+		results = append(results, sarifResults(id, index, rule, level, rule.Baselined, knownState)...)
+	}
+	return results
+}
+
+// sarifResults converts findings of an out-of-sync rule into SARIF results of
+// the given level and baseline state. Without findings, a single result
+// points at the rule's doc files.
+func sarifResults(id string, index int, rule RuleResult, level string, findings []assessor.Finding, baselineState string) []sarifResult {
+	var docPaths []string
+	if rule.DocFiles != nil {
+		docPaths = rule.DocFiles.Paths
+	}
+
+	if len(findings) == 0 {
+		return []sarifResult{{
+			RuleID:        id,
+			RuleIndex:     index,
+			Level:         level,
+			Message:       sarifMessage{Text: fmt.Sprintf("Documentation is out of sync with the code: %s", rule.Result.Reason)},
+			Locations:     sarifLocations(docPaths, 0),
+			BaselineState: baselineState,
+		}}
+	}
+
+	results := make([]sarifResult, 0, len(findings))
+	for _, f := range findings {
+		paths := docPaths
+		if f.DocFile != "" {
+			paths = []string{f.DocFile}
+		}
+		results = append(results, sarifResult{
+			RuleID:        id,
+			RuleIndex:     index,
+			Level:         level,
+			Message:       sarifMessage{Text: findingMessage(f)},
+			Locations:     sarifLocations(paths, f.Line),
+			BaselineState: baselineState,
+		})
+	}
+	return results
+}
+
+func sarifLocations(paths []string, line int) []sarifLocation {
+	locations := make([]sarifLocation, 0, len(paths))
+	for _, path := range paths {
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(path)},
+		}}
+		if line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+		}
+		locations = append(locations, location)
+	}
+	return locations
+}
+
+// findingMessage summarises a finding in a single paragraph.
+func findingMessage(f assessor.Finding) string {
+	msg := fmt.Sprintf("[%s] %s", f.Category, f.Description)
+	if code := strings.TrimSpace(f.CodeFile + " " + f.Symbol); code != "" {
+		msg += fmt.Sprintf(" (code: %s)", code)
+	}
+	if f.Suggestion != "" {
+		msg += " Suggestion: " + f.Suggestion
+	}
+	return msg
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// SARIFRuleID derives a stable SARIF rule ID from a rule name, e.g.
+// "User API Documentation" becomes "drift/user-api-documentation".
+func SARIFRuleID(name string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = "rule"
+	}
+	return toolName + "/" + slug
+}
+
+// sarifRuleIDs returns the SARIF rule ID of each rule of the report. Names
+// that differ only in case or punctuation, such as "API docs" and
+// "api-docs", share a slug, so every configured rule after the first with a
+// slug gets a numeric suffix, e.g. "drift/api-docs--2". Slugs never contain
+// "--", so a suffixed ID can't be another rule's ID. Rules are numbered in
+// the order of the configuration, so that their IDs are the same whichever
+// rules were triggered.
+func sarifRuleIDs(r *Report) []string {
+	names := r.RuleNames
+	if len(names) == 0 {
+		for _, rule := range r.Rules {
+			names = append(names, rule.Name)
+		}
+		names = append(names, r.SkippedRules...)
+	}
+
+	byName := make(map[string][]string, len(names))
+	count := make(map[string]int, len(names))
+	for _, name := range names {
+		id := SARIFRuleID(name)
+		count[id]++
+		if n := count[id]; n > 1 {
+			id = fmt.Sprintf("%s--%d", id, n)
+		}
+		byName[name] = append(byName[name], id)
+	}
+
+	ids := make([]string, len(r.Rules))
+	for i, rule := range r.Rules {
+		if queue := byName[rule.Name]; len(queue) > 0 {
+			ids[i], byName[rule.Name] = queue[0], queue[1:]
+		} else {
+			ids[i] = SARIFRuleID(rule.Name)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,240 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/report"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSARIFSchema compiles the official SARIF 2.1.0 schema from testdata.
+func loadSARIFSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+	f, err := os.Open("testdata/sarif-schema-2.1.0.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft4
+	require.NoError(t, compiler.AddResource("sarif-schema-2.1.0.json", f))
+	schema, err := compiler.Compile("sarif-schema-2.1.0.json")
+	require.NoError(t, err)
+	return schema
+}
+
+func TestWriteSARIF(t *testing.T) {
+	rep := sampleReport()
+	rep.ToolVersion = "1.2.3"
+	rep.Rules = append(rep.Rules, report.RuleResult{
+		Name:      "Readme",
+		Provider:  "gemini",
+		DocFiles:  &report.FileStats{Count: 2, Bytes: 10, Paths: []string{"README.md", "docs/index.md"}},
+		CodeFiles: &report.FileStats{Count: 1, Bytes: 10, Paths: []string{"main.go"}},
+		Status:    report.StatusOutOfSync,
+		Severity:  config.SeverityInfo,
+		Result:    &assessor.AssessmentResult{IsInSync: false, Reason: "the install command changed"},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, rep, report.FormatSARIF))
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	if err := loadSARIFSchema(t).Validate(doc); err != nil {
+		t.Fatalf("SARIF output does not validate against the schema: %#v\n%s", err, buf.String())
+	}
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Version string `json:"version"`
+					Rules   []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration *struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Invocations []struct {
+				ExecutionSuccessful        bool `json:"executionSuccessful"`
+				ToolExecutionNotifications []struct {
+					Message struct {
+						Text string `json:"text"`
+					} `json:"message"`
+				} `json:"toolExecutionNotifications"`
+			} `json:"invocations"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+
+	assert.Equal(t, "1.2.3", run.Tool.Driver.Version)
+	require.Len(t, run.Tool.Driver.Rules, 4)
+	assert.Equal(t, "drift/users", run.Tool.Driver.Rules[0].ID)
+	assert.Nil(t, run.Tool.Driver.Rules[0].DefaultConfiguration)
+	require.NotNil(t, run.Tool.Driver.Rules[3].DefaultConfiguration)
+	assert.Equal(t, "note", run.Tool.Driver.Rules[3].DefaultConfiguration.Level)
+
+	require.Len(t, run.Invocations, 1)
+	assert.False(t, run.Invocations[0].ExecutionSuccessful)
+	require.Len(t, run.Invocations[0].ToolExecutionNotifications, 1)
+	assert.Contains(t, run.Invocations[0].ToolExecutionNotifications[0].Message.Text, "syntax error")
+
+	// One result for the finding, located at its line, and one result for
+	// the rule without findings, located at each of its doc files.
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, "drift/auth", run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	require.Len(t, run.Results[0].Locations, 1)
+	assert.Equal(t, "docs/auth.md", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	require.NotNil(t, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, 3, run.Results[0].Locations[0].PhysicalLocation.Region.StartLine)
+
+	assert.Equal(t, "drift/readme", run.Results[1].RuleID)
+	assert.Equal(t, "note", run.Results[1].Level)
+	require.Len(t, run.Results[1].Locations, 2)
+	assert.Equal(t, "docs/index.md", run.Results[1].Locations[1].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, run.Results[1].Locations[1].PhysicalLocation.Region)
+}
+
+func TestWriteSARIF_InSync(t *testing.T) {
+	rep := &report.Report{Rules: []report.RuleResult{{Name: "Users", Status: report.StatusInSync}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSARIF(&buf, rep))
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.NoError(t, loadSARIFSchema(t).Validate(doc))
+	assert.Contains(t, buf.String(), `"results": []`)
+}
+
+func TestWriteSARIF_Warning(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSARIF(&buf, warningReport()))
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.NoError(t, loadSARIFSchema(t).Validate(doc))
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Len(t, log.Runs, 1)
+	require.Len(t, log.Runs[0].Results, 1)
+	assert.Equal(t, "drift/style", log.Runs[0].Results[0].RuleID)
+	assert.Equal(t, "warning", log.Runs[0].Results[0].Level)
+}
+
+func TestWriteSARIF_Baseline(t *testing.T) {
+	rep := driftReport(tokenFinding, scopeFinding)
+	rep.BaselinePath = ".drift-baseline.json"
+	rep.ApplyBaseline(report.NewBaseline(driftReport(tokenFinding)))
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSARIF(&buf, rep))
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.NoError(t, loadSARIFSchema(t).Validate(doc))
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID        string `json:"ruleId"`
+				BaselineState string `json:"baselineState"`
+				Message       struct {
+					Text string `json:"text"`
+				} `json:"message"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	results := log.Runs[0].Results
+	require.Len(t, results, 3)
+	assert.Equal(t, "new", results[0].BaselineState)
+	assert.Contains(t, results[0].Message.Text, "scope is a list")
+	assert.Equal(t, "unchanged", results[1].BaselineState)
+	assert.Contains(t, results[1].Message.Text, "token is not documented")
+	assert.Equal(t, "drift/readme", results[2].RuleID)
+	assert.Equal(t, "unchanged", results[2].BaselineState)
+}
+
+func TestSARIFRuleID(t *testing.T) {
+	assert.Equal(t, "drift/user-api-documentation", report.SARIFRuleID("User API Documentation"))
+	assert.Equal(t, "drift/cli-usage-docs", report.SARIFRuleID("  CLI: usage/docs! "))
+	assert.Equal(t, "drift/rule", report.SARIFRuleID("???"))
+}
+
+// sarifRuleIDs writes rep as SARIF and returns the IDs of its rule
+// descriptors.
+func sarifRuleIDs(t *testing.T, rep *report.Report) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, report.Write(&buf, rep, report.FormatSARIF))
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	ids := []string{}
+	for _, rule := range log.Runs[0].Tool.Driver.Rules {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+func TestWriteSARIF_UniqueRuleIDs(t *testing.T) {
+	names := []string{"API docs", "api-docs", "API docs 2", "API Docs!"}
+	rep := &report.Report{RuleNames: names}
+	for _, name := range names {
+		rep.Rules = append(rep.Rules, report.RuleResult{Name: name, Status: report.StatusInSync})
+	}
+	assert.Equal(t, []string{"drift/api-docs", "drift/api-docs--2", "drift/api-docs-2", "drift/api-docs--3"}, sarifRuleIDs(t, rep))
+
+	// A rule keeps its ID when the rules before it aren't triggered.
+	rep = &report.Report{
+		RuleNames:    names,
+		Rules:        []report.RuleResult{{Name: "api-docs", Status: report.StatusInSync}},
+		SkippedRules: []string{"API docs", "API docs 2", "API Docs!"},
+	}
+	assert.Equal(t, []string{"drift/api-docs--2"}, sarifRuleIDs(t, rep))
+}
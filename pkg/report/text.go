@@ -0,0 +1,149 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/driftee-ai/drift/pkg/assessor"
+)
+
+// WriteText renders the report in the human-readable format.
+func WriteText(w io.Writer, r *Report) error {
+	p := &textPrinter{w: w}
+
+	p.printf("Loaded %d rules from %s (provider: %s)\n", r.TotalRules, r.ConfigPath, r.Provider)
+	if len(r.ChangedFiles) > 0 {
+		p.printf("Filtering rules based on %d changed files. %d rules were triggered.\n", len(r.ChangedFiles), len(r.Rules))
+	}
+
+	for _, rule := range r.Rules {
+		p.printf("  - Rule: %s\n", rule.Name)
+		if rule.Provider != r.Provider || rule.Model != r.Model {
+			p.printf("    Provider: %s%s\n", rule.Provider, modelSuffix(rule.Model))
+		}
+		if rule.CodeFiles != nil {
+			p.printf("    Found %d code files, total size: %d bytes\n", rule.CodeFiles.Count, rule.CodeFiles.Bytes)
+		}
+		if rule.DocFiles != nil {
+			p.printf("    Found %d doc files, total size: %d bytes\n", rule.DocFiles.Count, rule.DocFiles.Bytes)
+		}
+		if n := len(rule.Suppressions); n > 0 {
+			p.printf("    Ignored %d regions of the docs marked with drift:ignore\n", n)
+		}
+
+		switch rule.Status {
+		case StatusInSync:
+			p.printf("    Result: In Sync\n")
+		case StatusOutOfSync:
+			p.printf("    Result: Out of Sync (%s)\n", rule.Result.Reason)
+			p.printSeverity(rule.Severity)
+			p.printFindings(rule.Result.Findings)
+		case StatusWarning:
+			p.printf("    Warning: Possibly Out of Sync (%s)\n", rule.Result.Reason)
+			p.printf("    Confidence %.2f is below the minimum of %.2f.\n", *rule.Result.Confidence, rule.MinConfidence)
+			p.printSeverity(rule.EffectiveSeverity())
+			p.printFindings(rule.Result.Findings)
+		case StatusBaselined:
+			p.printf("    Result: Out of Sync, accepted by the baseline (%s)\n", rule.Result.Reason)
+		case StatusError:
+			p.printf("    Error: %s\n", rule.Error)
+		}
+		if n := len(rule.Baselined); n > 0 {
+			p.printf("    %d findings are accepted by the baseline.\n", n)
+		}
+		if rule.Result != nil {
+			p.printDissent(rule.Result.Dissent)
+		}
+	}
+
+	if n := len(r.FixedBaseline); n > 0 {
+		p.printf("%d baseline entries are fixed and can be pruned from %s:\n", n, r.BaselinePath)
+		for _, e := range r.FixedBaseline {
+			p.printf("  - %s: %s\n", e.Rule, e.Description)
+		}
+	}
+	if n := r.Warnings(); n > 0 {
+		p.printf("%d rules have low-confidence drift.\n", n)
+	}
+	if n := r.Errors(); n > 0 {
+		p.printf("%d rules could not be checked.\n", n)
+	}
+	if r.HasFailingDrift() {
+		p.printf("Drift detected.\n")
+	} else if r.HasDrift() {
+		p.printf("Drift detected below --fail-on %s, which doesn't fail the check.\n", r.failOn())
+	}
+	return p.err
+}
+
+// textPrinter writes formatted lines and remembers the first write error.
+type textPrinter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *textPrinter) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+// printFindings prints each finding of an assessment on its own line.
+func (p *textPrinter) printFindings(findings []assessor.Finding) {
+	for _, f := range findings {
+		p.printf("      - [%s] %s\n", f.Category, f.Description)
+		if location := findingLocation(f); location != "" {
+			p.printf("        Docs: %s\n", location)
+		}
+		if f.CodeFile != "" || f.Symbol != "" {
+			p.printf("        Code: %s\n", strings.TrimSpace(f.CodeFile+" "+f.Symbol))
+		}
+		if f.Suggestion != "" {
+			p.printf("        Suggestion: %s\n", f.Suggestion)
+		}
+	}
+}
+
+// printSeverity prints the severity of a rule's drift, if known.
+func (p *textPrinter) printSeverity(severity string) {
+	if severity != "" {
+		p.printf("    Severity: %s\n", severity)
+	}
+}
+
+// printDissent prints the votes that disagreed with a consensus verdict.
+func (p *textPrinter) printDissent(dissent []assessor.Dissent) {
+	for _, d := range dissent {
+		switch {
+		case d.Error != "":
+			p.printf("    Dissent: %s failed: %s\n", d.Voter, d.Error)
+		case d.IsInSync:
+			p.printf("    Dissent: %s found it in sync\n", d.Voter)
+		default:
+			p.printf("    Dissent: %s found it out of sync (%s)\n", d.Voter, d.Reason)
+		}
+	}
+}
+
+// findingLocation formats where in the documentation a finding applies,
+// e.g. "docs/api/users.md:12 (Parameters)".
+func findingLocation(f assessor.Finding) string {
+	location := f.DocFile
+	if f.Line > 0 {
+		location += fmt.Sprintf(":%d", f.Line)
+	}
+	if f.Section != "" {
+		location = strings.TrimSpace(location + " (" + f.Section + ")")
+	}
+	return location
+}
+
+// modelSuffix formats a model name for display next to its provider.
+func modelSuffix(model string) string {
+	if model == "" {
+		return ""
+	}
+	return " (" + model + ")"
+}
@@ -1,13 +1,14 @@
 package rules
 
 import (
-	"github.com/bmatcuk/doublestar/v4"
 	"github.com/driftee-ai/drift/pkg/config"
+	"github.com/driftee-ai/drift/pkg/files"
 )
 
 // FilterTriggeredRules filters a list of rules, returning only those that are
 // "triggered" by a list of changed files. A rule is triggered if any of the
-// changed files match any of its 'code' or 'docs' glob patterns.
+// changed files match any of its 'code' or 'docs' glob patterns, and none of
+// its exclusions.
 // If the changedFiles list is empty, all rules are returned.
 func FilterTriggeredRules(rules []config.Rule, changedFiles []string) ([]config.Rule, error) {
 	if len(changedFiles) == 0 {
@@ -18,24 +19,13 @@ func FilterTriggeredRules(rules []config.Rule, changedFiles []string) ([]config.
 	for _, rule := range rules {
 		isTriggered := false
 		for _, changedFile := range changedFiles {
-			// Check against code globs
-			for _, glob := range rule.Code {
-				if match, err := doublestar.Match(glob, changedFile); err != nil {
+			// Check against code globs, then docs globs
+			for _, patterns := range [][]string{rule.CodePatterns(), rule.DocPatterns()} {
+				match, err := files.Match(patterns, changedFile)
+				if err != nil {
 					return nil, err
-				} else if match {
-					isTriggered = true
-					break
 				}
-			}
-			if isTriggered {
-				break
-			}
-
-			// Check against docs globs
-			for _, glob := range rule.Docs {
-				if match, err := doublestar.Match(glob, changedFile); err != nil {
-					return nil, err
-				} else if match {
+				if match {
 					isTriggered = true
 					break
 				}
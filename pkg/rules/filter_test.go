@@ -24,6 +24,13 @@ var mockRules = []config.Rule{
 		Code: []string{"pkg/utils/*.go", "pkg/helpers/*.go"},
 		Docs: []string{"docs/api/utils.md"},
 	},
+	{
+		Name:        "Rule4-Exclusions",
+		Code:        []string{"pkg/client/**/*.go", "!pkg/client/mocks/**"},
+		Docs:        []string{"docs/client/*.md"},
+		ExcludeCode: []string{"**/*_test.go"},
+		ExcludeDocs: []string{"docs/client/CHANGELOG.md"},
+	},
 }
 
 func TestFilterTriggeredRules(t *testing.T) {
@@ -36,7 +43,7 @@ func TestFilterTriggeredRules(t *testing.T) {
 		{
 			name:          "No changed files should return all rules",
 			changedFiles:  []string{},
-			expectedRules: []string{"Rule1-Go", "Rule2-JS", "Rule3-MultiGlob"},
+			expectedRules: []string{"Rule1-Go", "Rule2-JS", "Rule3-MultiGlob", "Rule4-Exclusions"},
 			expectErr:     false,
 		},
 		{
@@ -81,6 +88,16 @@ func TestFilterTriggeredRules(t *testing.T) {
 			expectedRules: []string{"Rule1-Go"},
 			expectErr:     false,
 		},
+		{
+			name:          "Code file outside the exclusions",
+			changedFiles:  []string{"pkg/client/client.go"},
+			expectedRules: []string{"Rule4-Exclusions"},
+		},
+		{
+			name:          "Excluded files should not trigger a rule",
+			changedFiles:  []string{"pkg/client/client_test.go", "pkg/client/mocks/client.go", "docs/client/CHANGELOG.md"},
+			expectedRules: []string{},
+		},
 		{
 			name:         "Invalid glob pattern should return an error",
 			changedFiles: []string{"test"},